@@ -0,0 +1,186 @@
+package gendure
+
+import (
+	"math"
+	"time"
+)
+
+// Backoff computes the delay between retry attempts.
+// Set on a retry with WithBackoff; the default is an exponential backoff built from the
+// initial delay and multiplier. Implementations must be safe for concurrent use.
+type Backoff interface {
+	// Delay returns the delay before the retry following the given zero-based attempt,
+	// before jitter. Must not be negative.
+	Delay(attempt int) time.Duration
+}
+
+// BackoffFunc adapts a function to the Backoff interface.
+//
+// Example:
+//
+//	backoff := BackoffFunc(func(attempt int) time.Duration {
+//	    return time.Duration(attempt+1) * time.Second
+//	})
+type BackoffFunc func(attempt int) time.Duration
+
+// Delay returns f(attempt).
+func (f BackoffFunc) Delay(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// constantBackoff waits the same delay between every attempt.
+type constantBackoff struct {
+	delay time.Duration
+}
+
+// NewConstantBackoff returns a Backoff waiting the same delay between every attempt.
+//
+// Parameters:
+//   - delay: Delay between attempts. If < 0, defaults to 0.
+//
+// Returns:
+//   - Backoff: delay, delay, delay, ...
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithBackoff(NewConstantBackoff(time.Second)))
+func NewConstantBackoff(delay time.Duration) Backoff {
+	return constantBackoff{delay: max(delay, 0)}
+}
+
+func (b constantBackoff) Delay(int) time.Duration {
+	return b.delay
+}
+
+// linearBackoff grows the delay by a fixed increment per attempt.
+type linearBackoff struct {
+	initial   time.Duration
+	increment time.Duration
+}
+
+// NewLinearBackoff returns a Backoff growing the delay by a fixed increment per attempt.
+//
+// Parameters:
+//   - initial: Delay after the first attempt. If < 0, defaults to 0.
+//   - increment: Delay added per attempt. If < 0, defaults to 0.
+//
+// Returns:
+//   - Backoff: initial, initial + increment, initial + 2*increment, ...
+//
+// Example:
+//
+//	backoff := NewLinearBackoff(100*time.Millisecond, 100*time.Millisecond) // 100ms, 200ms, 300ms, ...
+func NewLinearBackoff(initial, increment time.Duration) Backoff {
+	return linearBackoff{initial: max(initial, 0), increment: max(increment, 0)}
+}
+
+func (b linearBackoff) Delay(attempt int) time.Duration {
+	return saturatingDuration(float64(b.initial) + float64(b.increment)*float64(attempt))
+}
+
+// exponentialBackoff multiplies the delay by a constant factor per attempt.
+type exponentialBackoff struct {
+	initial    time.Duration
+	multiplier float64
+}
+
+// NewExponentialBackoff returns a Backoff multiplying the delay by a constant factor per attempt.
+// Fractional multipliers such as 1.5 give gentler growth than doubling.
+//
+// Parameters:
+//   - initial: Delay after the first attempt. If < 0, defaults to 0.
+//   - multiplier: Growth factor per attempt. If < 1, defaults to 2.
+//
+// Returns:
+//   - Backoff: initial, initial * multiplier, initial * multiplier^2, ...
+//
+// Example:
+//
+//	backoff := NewExponentialBackoff(100*time.Millisecond, 1.5) // 100ms, 150ms, 225ms, ...
+func NewExponentialBackoff(initial time.Duration, multiplier float64) Backoff {
+	if multiplier < 1 {
+		multiplier = defaultMultiplier
+	}
+
+	return exponentialBackoff{initial: max(initial, 0), multiplier: multiplier}
+}
+
+func (b exponentialBackoff) Delay(attempt int) time.Duration {
+	return saturatingDuration(float64(b.initial) * math.Pow(b.multiplier, float64(attempt)))
+}
+
+// fibonacciBackoff grows the delay along the Fibonacci sequence.
+type fibonacciBackoff struct {
+	initial time.Duration
+}
+
+// NewFibonacciBackoff returns a Backoff growing the delay along the Fibonacci sequence,
+// slower than doubling at first and still exponential in the long run.
+//
+// Parameters:
+//   - initial: Delay after the first attempt. If < 0, defaults to 0.
+//
+// Returns:
+//   - Backoff: initial, initial, 2*initial, 3*initial, 5*initial, 8*initial, ...
+//
+// Example:
+//
+//	backoff := NewFibonacciBackoff(100 * time.Millisecond) // 100ms, 100ms, 200ms, 300ms, 500ms, ...
+func NewFibonacciBackoff(initial time.Duration) Backoff {
+	return fibonacciBackoff{initial: max(initial, 0)}
+}
+
+func (b fibonacciBackoff) Delay(attempt int) time.Duration {
+	previous, current := 0.0, 1.0
+
+	for range attempt {
+		previous, current = current, previous+current
+
+		// Stop early once the delay saturates
+		if float64(b.initial)*current >= float64(maxDuration) {
+			return maxDuration
+		}
+	}
+
+	return saturatingDuration(float64(b.initial) * current)
+}
+
+// polynomialBackoff grows the delay as a power of the attempt number.
+type polynomialBackoff struct {
+	initial  time.Duration
+	exponent float64
+}
+
+// NewPolynomialBackoff returns a Backoff growing the delay as a power of the attempt number.
+//
+// Parameters:
+//   - initial: Delay after the first attempt. If < 0, defaults to 0.
+//   - exponent: Power applied to the attempt number. If <= 0, defaults to 2 (quadratic).
+//
+// Returns:
+//   - Backoff: initial * 1^exponent, initial * 2^exponent, initial * 3^exponent, ...
+//
+// Example:
+//
+//	backoff := NewPolynomialBackoff(100*time.Millisecond, 2) // 100ms, 400ms, 900ms, ...
+func NewPolynomialBackoff(initial time.Duration, exponent float64) Backoff {
+	if exponent <= 0 {
+		exponent = defaultPolynomialExponent
+	}
+
+	return polynomialBackoff{initial: max(initial, 0), exponent: exponent}
+}
+
+func (b polynomialBackoff) Delay(attempt int) time.Duration {
+	return saturatingDuration(float64(b.initial) * math.Pow(float64(attempt+1), b.exponent))
+}
+
+// saturatingDuration converts a delay in nanoseconds to a Duration,
+// saturating at the largest Duration instead of overflowing.
+func saturatingDuration(nanoseconds float64) time.Duration {
+	if nanoseconds >= float64(maxDuration) || math.IsNaN(nanoseconds) {
+		return maxDuration
+	}
+
+	return time.Duration(max(nanoseconds, 0))
+}
@@ -0,0 +1,125 @@
+//nolint:all // only test
+package gendure_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marincor/gendure"
+	"github.com/marincor/gendure/clocktest"
+)
+
+func TestBackoffStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		backoff gendure.Backoff
+		want    []time.Duration
+	}{
+		{
+			name:    "constant",
+			backoff: gendure.NewConstantBackoff(time.Second),
+			want:    []time.Duration{time.Second, time.Second, time.Second, time.Second},
+		},
+		{
+			name:    "linear",
+			backoff: gendure.NewLinearBackoff(time.Second, 500*time.Millisecond),
+			want:    []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second, 2500 * time.Millisecond},
+		},
+		{
+			name:    "exponential",
+			backoff: gendure.NewExponentialBackoff(time.Second, 1.5),
+			want:    []time.Duration{time.Second, 1500 * time.Millisecond, 2250 * time.Millisecond, 3375 * time.Millisecond},
+		},
+		{
+			name:    "fibonacci",
+			backoff: gendure.NewFibonacciBackoff(time.Second),
+			want:    []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second},
+		},
+		{
+			name:    "polynomial",
+			backoff: gendure.NewPolynomialBackoff(time.Second, 2),
+			want:    []time.Duration{time.Second, 4 * time.Second, 9 * time.Second, 16 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for attempt, want := range tt.want {
+				if got := tt.backoff.Delay(attempt); got != want {
+					t.Errorf("attempt %d: want %s, got %s", attempt, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffStrategiesSaturate(t *testing.T) {
+	t.Parallel()
+
+	backoffs := []gendure.Backoff{
+		gendure.NewLinearBackoff(time.Hour, time.Hour),
+		gendure.NewExponentialBackoff(time.Hour, 2),
+		gendure.NewFibonacciBackoff(time.Hour),
+		gendure.NewPolynomialBackoff(time.Hour, 10),
+	}
+
+	for _, backoff := range backoffs {
+		previous := time.Duration(0)
+
+		for _, attempt := range []int{1, 10, 100, 1000, 1 << 30} {
+			delay := backoff.Delay(attempt)
+			if delay < previous {
+				t.Errorf("%T: want non-decreasing delays, got %s after %s at attempt %d", backoff, delay, previous, attempt)
+			}
+
+			previous = delay
+		}
+	}
+}
+
+func TestExponentialBackoffRetryWithBackoff(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	callCount := 0
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			callCount++
+			if callCount < 4 {
+				return "", errors.New("temporary error")
+			}
+
+			return success, nil
+		},
+		gendure.WithMaxRetries(4),
+		gendure.WithBackoff(gendure.NewLinearBackoff(time.Second, time.Second)),
+		gendure.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		clock.BlockUntilTimers(1)
+
+		if wait := clock.Timers()[0].Sub(clock.Now()); wait != delay {
+			t.Errorf("want delay %s, got %s", delay, wait)
+		}
+
+		clock.Advance(delay)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf(errorWantSuccessGotError, err)
+	}
+}
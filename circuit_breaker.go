@@ -2,6 +2,7 @@ package gendure
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"sync/atomic"
 	"time"
@@ -19,14 +20,16 @@ const (
 	// After recoveryTimeout, transitions to HalfOpen to test if the service recovered.
 	Open
 
-	// HalfOpen state allows a single request to test service health.
-	// On success, transitions back to Closed. On failure, transitions back to Open.
+	// HalfOpen state allows a limited number of trial requests to test service health.
+	// Once enough trial requests succeed, transitions back to Closed.
+	// When the required successes can no longer be reached, transitions back to Open.
 	HalfOpen
 )
 
-// circuitBreaker implements the Circuit Breaker resilience pattern for operations returning type T.
+// CircuitBreaker implements the Circuit Breaker resilience pattern for operations returning type T.
 // It prevents cascading failures by blocking requests to failing services and providing
 // automatic recovery attempts after a cooldown period.
+// Create instances with NewCircuitBreaker; the zero value is not ready for use.
 //
 // Type Parameters:
 //   - T: The return type of the protected operation
@@ -34,8 +37,8 @@ const (
 // The circuit breaker has three states:
 //   - Closed: Normal operation, requests pass through
 //   - Open: Failure threshold exceeded, requests are blocked
-//   - HalfOpen: Testing if service recovered, allows one request
-type circuitBreaker[T any] struct {
+//   - HalfOpen: Testing if service recovered, allows a limited number of trial requests
+type CircuitBreaker[T any] struct {
 	// lastFailureTime stores the timestamp of the most recent failure.
 	// Used to determine when to transition from Open to HalfOpen state.
 	lastFailureTime atomic.Value
@@ -64,9 +67,91 @@ type circuitBreaker[T any] struct {
 	// If nil, logging is disabled.
 	glogger glogger.GLogger
 
-	// halfOpenLock ensures only one request tests the service in HalfOpen state.
-	// Prevents multiple concurrent requests from executing simultaneously during recovery testing.
-	halfOpenLock atomic.Bool
+	// halfOpenPeriod identifies the current HalfOpen period. It changes whenever the circuit
+	// leaves HalfOpen, so trial requests still in flight from an earlier period are not counted.
+	halfOpenPeriod atomic.Uint32
+
+	// halfOpenAdmitted counts the trial requests admitted in the current HalfOpen period.
+	// Prevents more than permittedHalfOpenCalls requests from testing the service during recovery.
+	halfOpenAdmitted trialCounter
+
+	// halfOpenSuccesses counts the successful trial requests in the current HalfOpen period.
+	halfOpenSuccesses trialCounter
+
+	// halfOpenFailures counts the failed trial requests in the current HalfOpen period.
+	halfOpenFailures trialCounter
+
+	// permittedHalfOpenCalls is the number of trial requests allowed in HalfOpen state.
+	permittedHalfOpenCalls int32
+
+	// requiredHalfOpenSuccesses is the number of successful trial requests needed to close the circuit.
+	requiredHalfOpenSuccesses int32
+
+	// window aggregates the outcomes of the most recent calls.
+	// Used to evaluate the failure rate and exposed through Metrics.
+	window slidingWindow
+
+	// failureRateThreshold is the failure percentage that opens the circuit.
+	// Zero keeps the consecutive failure mode driven by failureThreshold.
+	failureRateThreshold float64
+
+	// minimumCalls is the number of calls the window must hold before rates are evaluated.
+	minimumCalls int64
+
+	// slowCallDurationThreshold is the duration above which a call is recorded as slow.
+	// Zero disables slow call detection.
+	slowCallDurationThreshold time.Duration
+
+	// slowCallRateThreshold is the slow call percentage that opens the circuit.
+	slowCallRateThreshold float64
+
+	// lastCallDuration stores the duration, in nanoseconds, of the most recently finished call.
+	lastCallDuration atomic.Int64
+
+	// listeners are called synchronously on every state transition.
+	listeners []func(StateChange)
+
+	// subscribers receive every state transition on their channels.
+	subscribers stateChangeSubscribers
+
+	// classifier decides which operation errors count as failures, successes or are ignored.
+	classifier errorClassifier
+
+	// recoverPanics converts operation panics into *PanicError failures.
+	recoverPanics bool
+
+	// repanic re-panics with the *PanicError once the failure has been accounted.
+	repanic bool
+
+	// clock provides the current time for durations, recovery timeouts and sliding windows.
+	clock Clock
+}
+
+// CircuitBreakerMetrics is a point-in-time view of a circuit breaker's state and call statistics.
+type CircuitBreakerMetrics struct {
+	// State is the current circuit breaker state (Closed, Open, or HalfOpen).
+	State int32
+
+	// ConsecutiveFailures is the number of consecutive failures since the last success.
+	ConsecutiveFailures int32
+
+	// Calls is the number of calls currently held by the sliding window.
+	Calls int64
+
+	// FailedCalls is the number of failed calls currently held by the sliding window.
+	FailedCalls int64
+
+	// FailureRate is the percentage (0-100) of failed calls in the sliding window.
+	FailureRate float64
+
+	// SlowCalls is the number of slow calls currently held by the sliding window.
+	SlowCalls int64
+
+	// SlowCallRate is the percentage (0-100) of slow calls in the sliding window.
+	SlowCallRate float64
+
+	// LastCallDuration is the duration of the most recently finished call.
+	LastCallDuration time.Duration
 }
 
 // getTypeName extracts the string representation of a type T.
@@ -97,34 +182,131 @@ func getTypeName[T any](t T) string {
 //     Must be greater than 0. If <= 0, defaults to 30 seconds.
 //     Typical values range from seconds to minutes depending on the service.
 //   - logger: Optional logger for debugging and monitoring. Pass nil to disable logging.
+//   - opts: Optional settings such as a failure-rate threshold over a sliding window.
+//     Invalid options are skipped and their defaults kept; use NewCircuitBreakerWithOptions
+//     to get a descriptive error instead.
 //
 // Returns:
-//   - *circuitBreaker[T]: A new circuit breaker instance ready for use
+//   - *CircuitBreaker[T]: A new circuit breaker instance ready for use
 //
 // Example:
 //
 //	cb := NewCircuitBreaker[string](3, 30*time.Second, myLogger)
+//
+//	// Open when 40% of the last 50 calls failed, once at least 20 calls were made
+//	cb := NewCircuitBreaker[string](3, 30*time.Second, myLogger,
+//	    WithCountSlidingWindow(50),
+//	    WithFailureRateThreshold(40),
+//	    WithMinimumCalls(20),
+//	)
 func NewCircuitBreaker[T any](
 	failureThreshold int32,
 	recoveryTimeout time.Duration,
 	logger glogger.GLogger,
-) *circuitBreaker[T] {
-	var tName T
+	opts ...CircuitBreakerOption,
+) *CircuitBreaker[T] {
+	cfg := defaultCircuitBreakerConfig()
+	cfg.logger = logger
+
+	if failureThreshold > 0 {
+		cfg.failureThreshold = failureThreshold
+	}
+
+	if recoveryTimeout > 0 {
+		cfg.recoveryTimeout = recoveryTimeout
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			_ = opt.applyCircuitBreaker(&cfg)
+		}
+	}
 
-	if failureThreshold <= 0 {
-		failureThreshold = defaultFailureThreshold
+	if cfg.windowDuration <= 0 && cfg.minimumCalls > cfg.windowSize {
+		cfg.minimumCalls = cfg.windowSize
 	}
 
-	if recoveryTimeout <= 0 {
-		recoveryTimeout = defaultRecoveryTimeout
+	return newCircuitBreaker[T](cfg)
+}
+
+// NewCircuitBreakerWithOptions creates and initializes a new circuit breaker instance configured
+// only through options. Unlike NewCircuitBreaker, invalid settings are reported instead of being
+// replaced by defaults, so new settings can be added without breaking callers.
+// The circuit breaker starts in Closed state, allowing all requests to pass through.
+//
+// Type Parameters:
+//   - T: The return type of operations this circuit breaker will protect
+//
+// Parameters:
+//   - opts: Settings such as WithFailureThreshold, WithRecoveryTimeout and WithLogger.
+//     Settings not provided use the same defaults as NewCircuitBreaker.
+//
+// Returns:
+//   - *CircuitBreaker[T]: A new circuit breaker instance ready for use, or nil on error
+//   - error: Every invalid setting joined together, each wrapping ErrInvalidOption
+//
+// Example:
+//
+//	cb, err := NewCircuitBreakerWithOptions[string](
+//	    WithFailureThreshold(5),
+//	    WithRecoveryTimeout(30*time.Second),
+//	    WithLogger(myLogger),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+func NewCircuitBreakerWithOptions[T any](opts ...CircuitBreakerOption) (*CircuitBreaker[T], error) {
+	cfg := defaultCircuitBreakerConfig()
+
+	var errs []error
+
+	for _, opt := range opts {
+		if opt == nil {
+			errs = append(errs, invalidOption("circuit breaker option cannot be nil"))
+
+			continue
+		}
+
+		if err := opt.applyCircuitBreaker(&cfg); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	circuitBreaker := &circuitBreaker[T]{
-		state:            atomic.Int32{},
-		failureThreshold: failureThreshold,
-		recoveryTimeout:  recoveryTimeout,
-		typeName:         getTypeName(tName),
-		glogger:          logger,
+	if len(errs) == 0 {
+		if err := cfg.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return newCircuitBreaker[T](cfg), nil
+}
+
+// newCircuitBreaker builds a Closed circuit breaker from a complete configuration.
+func newCircuitBreaker[T any](cfg circuitBreakerConfig) *CircuitBreaker[T] {
+	var tName T
+
+	circuitBreaker := &CircuitBreaker[T]{
+		state:                     atomic.Int32{},
+		failureThreshold:          cfg.failureThreshold,
+		recoveryTimeout:           cfg.recoveryTimeout,
+		typeName:                  getTypeName(tName),
+		glogger:                   cfg.logger,
+		window:                    cfg.newWindow(),
+		failureRateThreshold:      cfg.failureRateThreshold,
+		minimumCalls:              int64(cfg.minimumCalls),
+		slowCallDurationThreshold: cfg.slowCallDurationThreshold,
+		slowCallRateThreshold:     cfg.slowCallRateThreshold,
+		permittedHalfOpenCalls:    int32(cfg.permittedHalfOpenCalls),
+		requiredHalfOpenSuccesses: int32(cfg.requiredHalfOpenSuccesses),
+		listeners:                 cfg.listeners,
+		classifier:                cfg.classifier,
+		recoverPanics:             cfg.recoverPanics,
+		repanic:                   cfg.repanic,
+		clock:                     cfg.clock,
 	}
 
 	circuitBreaker.state.Store(Closed)
@@ -136,26 +318,30 @@ func NewCircuitBreaker[T any](
 // Behavior depends on circuit state:
 //   - Closed: Execute operation normally
 //   - Open: Skip operation and call fallback immediately (unless recovery timeout elapsed)
-//   - HalfOpen: Execute operation as a test; success closes circuit, failure reopens it
+//   - HalfOpen: Execute operation as a trial; enough successes close the circuit, failures reopen it
 //
 // Context cancellation is checked before executing the operation. If the context is cancelled,
 // the fallback is called immediately without executing the main operation.
 //
-// In HalfOpen state, only one request is allowed to test the service at a time.
-// Concurrent requests during HalfOpen will use the fallback instead.
+// In HalfOpen state, only the permitted number of trial requests test the service
+// (one by default, see WithHalfOpenCalls). Further requests during HalfOpen use the fallback instead.
 //
 // This method is thread-safe and can be called concurrently.
 //
 // Parameters:
 //   - ctx: Context for cancellation control. If cancelled, fallback is called immediately.
 //   - operation: The primary function to execute. Should return (T, error).
-//     Returning an error increments the failure count.
+//     Returning an error increments the failure count, unless the error is classified
+//     as ignored or successful (see WithIgnoredErrors, WithSuccessErrors, WithIsFailure),
+//     in which case the result and error are returned as is.
 //   - fallback: Function called when circuit is Open, operation fails, or context is cancelled.
-//     Provides degraded functionality or cached responses.
+//     Provides degraded functionality or cached responses. May be nil, in which case
+//     the reason is returned as an error instead (see Do).
 //
 // Returns:
 //   - T: Result from either operation (on success) or fallback (on failure/open circuit/cancelled context)
-//   - error: Error from fallback function, or nil if operation succeeded
+//   - error: Error from fallback function, or nil if operation succeeded.
+//     Without fallback, the same errors as Do.
 //
 // Example:
 //
@@ -165,77 +351,565 @@ func NewCircuitBreaker[T any](
 //	    func() (string, error) { return httpClient.Get(url) },
 //	    func() (string, error) { return cachedValue, nil },
 //	)
-func (cb *circuitBreaker[T]) Execute(
+//
+//	// Without fallback, rejected calls can be answered with 503 Service Unavailable
+//	result, err = cb.Execute(ctx, func() (string, error) { return httpClient.Get(url) }, nil)
+//	var cbErr *CircuitBreakerError
+//	if errors.As(err, &cbErr) && errors.Is(err, ErrCircuitOpen) {
+//	    w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(cbErr.RetryAfter.Seconds()))))
+//	    w.WriteHeader(http.StatusServiceUnavailable)
+//	}
+func (cb *CircuitBreaker[T]) Execute(
 	ctx context.Context,
 	operation func() (T, error),
 	fallback func() (T, error),
 ) (T, error) {
+	result, useFallback, err := cb.execute(ctx, func(context.Context) (T, error) {
+		return operation()
+	})
+	if useFallback && fallback != nil {
+		return fallback()
+	}
+
+	return result, err
+}
+
+// FallbackFunc is a fallback that receives the reason it was invoked.
+// Used by ExecuteWithFallback so a single fallback can choose between serving stale data,
+// returning a default, or propagating the cause.
+//
+// Parameters:
+//   - ctx: The context passed to ExecuteWithFallback
+//   - cause: Why the fallback was invoked: ctx.Err() if the context is done, or a
+//     *CircuitBreakerError matching ErrCircuitOpen, ErrHalfOpenBusy, or ErrOperationFailed
+//     together with the operation error
+//
+// Returns:
+//   - T: The degraded result
+//   - error: Error returned to the caller, or nil
+type FallbackFunc[T any] func(ctx context.Context, cause error) (T, error)
+
+// ExecuteWithFallback runs the operation with circuit breaker protection like Execute,
+// but passes the context and the reason to the fallback.
+//
+// This method is thread-safe and can be called concurrently.
+//
+// Parameters:
+//   - ctx: Context for cancellation control, also passed to the fallback
+//   - operation: The primary function to execute. Failures are accounted as in Execute.
+//   - fallback: Function called with the cause when the circuit rejects the call, the operation
+//     fails, or the context is done. May be nil, in which case the cause is returned as an error.
+//
+// Returns:
+//   - T: Result from either operation (on success) or fallback
+//   - error: Error from fallback function, or nil if operation succeeded
+//
+// Example:
+//
+//	result, err := cb.ExecuteWithFallback(
+//	    ctx,
+//	    func() (string, error) { return httpClient.Get(url) },
+//	    func(ctx context.Context, cause error) (string, error) {
+//	        switch {
+//	        case errors.Is(cause, ErrCircuitOpen):
+//	            return cache.Get(url), nil
+//	        case errors.Is(cause, ErrOperationFailed):
+//	            return defaultValue, nil
+//	        default:
+//	            return "", cause
+//	        }
+//	    },
+//	)
+func (cb *CircuitBreaker[T]) ExecuteWithFallback(
+	ctx context.Context,
+	operation func() (T, error),
+	fallback FallbackFunc[T],
+) (T, error) {
+	result, useFallback, err := cb.execute(ctx, func(context.Context) (T, error) {
+		return operation()
+	})
+	if useFallback && fallback != nil {
+		return fallback(ctx, err)
+	}
+
+	return result, err
+}
+
+// ExecuteContext runs a context-aware operation with circuit breaker protection and passes the
+// context and the reason to the fallback, like ExecuteWithFallback.
+//
+// The context reaches the operation, so HTTP or database calls can abort when it is cancelled
+// mid-call. A call that fails because its context was cancelled is neither recorded as a failure
+// nor as a success: the caller gave up, which says nothing about the dependency. The fallback is
+// then called with ctx.Err() as the cause. A call that fails because its context deadline passed
+// is classified like any other error, so a dependency hanging past its deadlines still opens the
+// circuit; ignore context.DeadlineExceeded with WithIgnoredErrors to opt out.
+//
+// This method is thread-safe and can be called concurrently.
+//
+// Parameters:
+//   - ctx: Context for cancellation control, passed to the operation and the fallback
+//   - operation: The primary function to execute. Failures are accounted as in Execute.
+//   - fallback: Function called with the cause when the circuit rejects the call, the operation
+//     fails, or the context is done. May be nil, in which case the cause is returned as an error.
+//
+// Returns:
+//   - T: Result from either operation (on success) or fallback
+//   - error: Error from fallback function, or nil if operation succeeded
+//
+// Example:
+//
+//	result, err := cb.ExecuteContext(
+//	    ctx,
+//	    func(ctx context.Context) (*http.Response, error) {
+//	        req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	        return http.DefaultClient.Do(req)
+//	    },
+//	    nil,
+//	)
+func (cb *CircuitBreaker[T]) ExecuteContext(
+	ctx context.Context,
+	operation func(ctx context.Context) (T, error),
+	fallback FallbackFunc[T],
+) (T, error) {
+	result, useFallback, err := cb.execute(ctx, operation)
+	if useFallback && fallback != nil {
+		return fallback(ctx, err)
+	}
+
+	return result, err
+}
+
+// Do runs the operation with circuit breaker protection and without fallback,
+// implementing the Policy interface.
+// The context is passed to the operation so it can abort in-flight work on cancellation.
+// As in ExecuteContext, a call failing because its context is done is not recorded.
+//
+// This method is thread-safe and can be called concurrently.
+//
+// Parameters:
+//   - ctx: Context for cancellation control, passed to the operation
+//   - operation: The primary function to execute. Failures are accounted as in Execute.
+//
+// Returns:
+//   - T: Result from the operation, or zero value if the operation was not executed
+//   - error: ctx.Err() if the context was done before or during the call, or a *CircuitBreakerError
+//     matching ErrCircuitOpen if the circuit is Open, ErrHalfOpenBusy (which also matches
+//     ErrCircuitOpen) if every HalfOpen trial call is in flight, or ErrOperationFailed and the
+//     operation error if the operation failed. Errors classified as ignored or successful
+//     are returned as is.
+//
+// Example:
+//
+//	result, err := cb.Do(ctx, func(ctx context.Context) (string, error) {
+//	    return fetch(ctx, url)
+//	})
+//	if errors.Is(err, ErrCircuitOpen) {
+//	    return http.StatusServiceUnavailable
+//	}
+func (cb *CircuitBreaker[T]) Do(
+	ctx context.Context,
+	operation func(ctx context.Context) (T, error),
+) (T, error) {
+	result, _, err := cb.execute(ctx, operation)
+
+	return result, err
+}
+
+// execute runs the operation with circuit breaker protection and accounts its outcome.
+// Shared by Execute and Do, which differ only in how a rejected or failed call is answered.
+//
+// Parameters:
+//   - ctx: Context for cancellation control, passed to the operation
+//   - operation: The primary function to execute
+//
+// Returns:
+//   - T: Result from the operation, or zero value if the operation was not executed
+//   - bool: true if the caller must answer with its fallback (cancelled context,
+//     rejected call or failed operation)
+//   - error: ctx.Err(), a *CircuitBreakerError, or an operation error classified as
+//     ignored or successful
+func (cb *CircuitBreaker[T]) execute(
+	ctx context.Context,
+	operation func(ctx context.Context) (T, error),
+) (T, bool, error) {
+	var zero T
+
 	select {
 	case <-ctx.Done():
-		return fallback()
+		return zero, true, ctx.Err()
 	default:
 		// Check if circuit is Open
 		if cb.state.Load() == Open {
 			lastFailureTime, ok := cb.lastFailureTime.Load().(time.Time)
 			// Transition to HalfOpen if recovery timeout has elapsed
-			if ok && time.Since(lastFailureTime) > cb.recoveryTimeout {
-				cb.state.Store(HalfOpen)
+			if ok && cb.clock.Now().Sub(lastFailureTime) > cb.recoveryTimeout {
+				cb.transition(ctx, Open, HalfOpen, nil)
 			} else {
-				// Circuit still Open, return fallback immediately
-				return fallback()
+				// Circuit still Open, reject the call immediately
+				return zero, true, &CircuitBreakerError{
+					Err:        ErrCircuitOpen,
+					State:      Open,
+					RetryAfter: cb.retryAfter(lastFailureTime, ok),
+				}
 			}
 		}
 
+		// Read the period before the state, so a period that ended meanwhile admits no trial
+		var trial halfOpenTrial
+
+		period := cb.halfOpenPeriod.Load()
 		if cb.state.Load() == HalfOpen {
-			if !cb.halfOpenLock.CompareAndSwap(false, true) {
-				return fallback()
+			trial = halfOpenTrial{admitted: true, period: period}
+			if !cb.acquireHalfOpenPermit(period) {
+				return zero, true, &CircuitBreakerError{Err: ErrHalfOpenBusy, State: HalfOpen}
 			}
-
-			defer cb.halfOpenLock.Store(false)
 		}
 
 		// Execute the operation
-		result, err := operation()
+		result, duration, recovered, err := cb.invoke(ctx, operation, trial)
+		if recovered {
+			return result, true, err
+		}
+
 		if err != nil {
-			cb.handleFailure(ctx)
+			// The caller cancelled mid-call: the outcome says nothing about the dependency.
+			// A deadline is not dropped, since a dependency hanging past it is unhealthy.
+			if ctxErr := ctx.Err(); errors.Is(ctxErr, context.Canceled) && errors.Is(err, ctxErr) {
+				cb.handleIgnored(trial)
 
-			return fallback()
+				return result, true, ctxErr
+			}
+
+			switch cb.classifier.classify(err) {
+			case errorClassIgnored:
+				cb.handleIgnored(trial)
+
+				return result, false, err
+			case errorClassSuccess:
+				cb.handleSuccess(ctx, duration, trial)
+
+				return result, false, err
+			case errorClassFailure:
+			}
+
+			state := cb.state.Load()
+			cb.handleFailure(ctx, err, duration, trial)
+
+			return result, true, &CircuitBreakerError{Err: ErrOperationFailed, Cause: err, State: state}
+		}
+
+		cb.handleSuccess(ctx, duration, trial)
+
+		return result, false, nil
+	}
+}
+
+// invoke runs the operation and measures its duration. A panic is recorded as a failure,
+// releasing the HalfOpen trial slot, before it propagates. With panic recovery enabled it is
+// converted into a *PanicError instead, unless re-panicking was requested.
+//
+// Parameters:
+//   - ctx: Context passed to the operation
+//   - operation: The primary function to execute
+//   - trial: The HalfOpen trial the call was admitted as, if any
+//
+// Returns:
+//   - T: Result from the operation, or zero value if it panicked
+//   - time.Duration: How long the operation ran
+//   - bool: true if a panic was recovered and already accounted as a failure
+//   - error: The operation error, or a *CircuitBreakerError wrapping the *PanicError
+func (cb *CircuitBreaker[T]) invoke(
+	ctx context.Context,
+	operation func(ctx context.Context) (T, error),
+	trial halfOpenTrial,
+) (result T, duration time.Duration, recovered bool, err error) {
+	start := cb.clock.Now()
+
+	defer func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+
+		// Still panicking: the stack of the operation has not unwound yet
+		panicErr := newPanicError(value)
+		duration = cb.clock.Now().Sub(start)
+
+		state := cb.state.Load()
+		cb.handleFailure(ctx, panicErr, duration, trial)
+
+		if !cb.recoverPanics {
+			panic(value)
+		}
+
+		if cb.repanic {
+			panic(panicErr)
+		}
+
+		var zero T
+
+		result, recovered = zero, true
+		err = &CircuitBreakerError{Err: ErrOperationFailed, Cause: panicErr, State: state}
+	}()
+
+	result, err = operation(ctx)
+	duration = cb.clock.Now().Sub(start)
+
+	return result, duration, false, err
+}
+
+// retryAfter returns the remaining time until an Open circuit admits trial calls again.
+//
+// Parameters:
+//   - lastFailureTime: Time the circuit opened
+//   - ok: Whether lastFailureTime is known
+//
+// Returns:
+//   - time.Duration: Remaining recovery time, or zero if unknown or already elapsed
+func (cb *CircuitBreaker[T]) retryAfter(lastFailureTime time.Time, ok bool) time.Duration {
+	if !ok {
+		return 0
+	}
+
+	return max(cb.recoveryTimeout-cb.clock.Now().Sub(lastFailureTime), 0)
+}
+
+// acquireHalfOpenPermit admits a trial request in the given HalfOpen period.
+// Returns false when every permitted trial request of the period was already admitted,
+// or when the period already ended.
+func (cb *CircuitBreaker[T]) acquireHalfOpenPermit(period uint32) bool {
+	_, ok := cb.halfOpenAdmitted.add(period, 1, cb.permittedHalfOpenCalls)
+
+	return ok
+}
+
+// handleIgnored releases the trial slot of a HalfOpen trial request whose error is ignored,
+// so it neither counts toward closing nor reopening the circuit.
+// A trial request from a HalfOpen period that already ended releases nothing.
+//
+// Parameters:
+//   - trial: The HalfOpen trial the call was admitted as, if any
+func (cb *CircuitBreaker[T]) handleIgnored(trial halfOpenTrial) {
+	if trial.admitted {
+		cb.halfOpenAdmitted.add(trial.period, -1, cb.permittedHalfOpenCalls)
+	}
+}
+
+// handleSuccess records a successful call in the sliding window and resets the consecutive
+// failure counter. A successful trial request counts toward closing the circuit, while in
+// failure-rate or slow call mode a success can still open a Closed circuit once the window
+// holds enough calls.
+//
+// This method is called internally when an operation succeeds.
+//
+// Parameters:
+//   - ctx: Context passed for logging purposes
+//   - duration: Time the operation took to complete
+//   - trial: The HalfOpen trial the call was admitted as, if any
+func (cb *CircuitBreaker[T]) handleSuccess(ctx context.Context, duration time.Duration, trial halfOpenTrial) {
+	now := cb.clock.Now()
+	outcome := cb.outcome(outcomeRecorded, duration)
+
+	cb.window.record(outcome, now)
+
+	if trial.admitted {
+		// A slow trial request does not prove the service recovered
+		if outcome&outcomeSlow != 0 && cb.slowCallRateThreshold > 0 {
+			cb.handleTrialFailure(ctx, nil, trial)
+
+			return
 		}
 
-		// Operation succeeded, reset failure counter and ensure circuit is Closed
-		cb.Reset()
+		// A trial request from a HalfOpen period that already ended is not counted
+		if successes, ok := cb.halfOpenSuccesses.increment(trial.period); ok &&
+			successes >= cb.requiredHalfOpenSuccesses {
+			cb.transition(ctx, HalfOpen, Closed, nil)
+		}
+
+		return
+	}
 
-		return result, nil
+	if cb.state.Load() != Closed {
+		return
+	}
+
+	cb.failureCount.Store(0)
+
+	if (cb.failureRateThreshold > 0 || cb.slowCallRateThreshold > 0) && cb.shouldOpen(0, now) {
+		cb.transition(ctx, Closed, Open, nil)
 	}
 }
 
-// handleFailure increments the failure counter and transitions the circuit to Open state
-// if the failure threshold is reached or if already in HalfOpen state.
+// handleFailure increments the failure counter, records the failure in the sliding window
+// and transitions the circuit to Open state if the failure threshold or failure rate is
+// reached, or if a trial request can no longer close the circuit.
 //
 // This method is called internally when an operation fails.
-// Logs debug information when circuit opens (if logger is configured).
 //
 // Parameters:
 //   - ctx: Context passed for logging purposes
-func (cb *circuitBreaker[T]) handleFailure(ctx context.Context) {
+//   - err: Error returned by the operation, reported to state change listeners
+//   - duration: Time the operation took to fail
+//   - trial: The HalfOpen trial the call was admitted as, if any
+func (cb *CircuitBreaker[T]) handleFailure(ctx context.Context, err error, duration time.Duration, trial halfOpenTrial) {
 	currentFailures := cb.failureCount.Add(1)
+	now := cb.clock.Now()
 
-	// Open circuit if threshold reached or if testing in HalfOpen failed
-	if currentFailures >= cb.failureThreshold || cb.state.Load() == HalfOpen {
-		if cb.glogger != nil {
-			cb.glogger.Debug(
-				ctx,
-				"Gendure Circuit breaker action",
-				"type_name", cb.typeName,
-				"failure_count", cb.failureCount.Load(),
-			)
-		}
+	cb.window.record(cb.outcome(outcomeRecorded|outcomeFailure, duration), now)
+
+	if trial.admitted {
+		cb.handleTrialFailure(ctx, err, trial)
+
+		return
+	}
+
+	// Open circuit if threshold reached
+	if cb.state.Load() == Closed && cb.shouldOpen(currentFailures, now) {
+		cb.transition(ctx, Closed, Open, err)
+	}
+}
+
+// handleTrialFailure counts a failed trial request and reopens the circuit as soon as
+// the remaining trial requests can no longer reach the required number of successes.
+// A trial request from a HalfOpen period that already ended is not counted.
+//
+// Parameters:
+//   - ctx: Context passed for logging purposes
+//   - err: Error returned by the trial request, nil for a slow success
+//   - trial: The HalfOpen trial the call was admitted as
+func (cb *CircuitBreaker[T]) handleTrialFailure(ctx context.Context, err error, trial halfOpenTrial) {
+	failures, ok := cb.halfOpenFailures.increment(trial.period)
 
-		cb.state.Store(Open)
-		cb.lastFailureTime.Store(time.Now())
+	if ok && failures > cb.permittedHalfOpenCalls-cb.requiredHalfOpenSuccesses {
+		cb.transition(ctx, HalfOpen, Open, err)
 	}
 }
 
+// outcome stores the call duration as the last call duration and marks the outcome
+// as slow when it exceeds the slow call duration threshold.
+func (cb *CircuitBreaker[T]) outcome(outcome callOutcome, duration time.Duration) callOutcome {
+	cb.lastCallDuration.Store(int64(duration))
+
+	if cb.slowCallDurationThreshold > 0 && duration > cb.slowCallDurationThreshold {
+		outcome |= outcomeSlow
+	}
+
+	return outcome
+}
+
+// shouldOpen reports whether the Closed circuit must open.
+// In consecutive mode the failure count is compared against failureThreshold.
+// In failure-rate mode the sliding window must hold at least minimumCalls calls and
+// its failure rate must reach failureRateThreshold.
+// In both modes, the slow call rate is compared against slowCallRateThreshold when enabled.
+func (cb *CircuitBreaker[T]) shouldOpen(consecutiveFailures int32, now time.Time) bool {
+	if cb.failureRateThreshold <= 0 && cb.slowCallRateThreshold <= 0 {
+		return consecutiveFailures >= cb.failureThreshold
+	}
+
+	snapshot := cb.window.snapshot(now)
+	evaluable := snapshot.calls >= cb.minimumCalls
+
+	if cb.slowCallRateThreshold > 0 && evaluable && snapshot.slowCallRate() >= cb.slowCallRateThreshold {
+		return true
+	}
+
+	if cb.failureRateThreshold <= 0 {
+		return consecutiveFailures >= cb.failureThreshold
+	}
+
+	return evaluable && snapshot.failureRate() >= cb.failureRateThreshold
+}
+
+// transition moves the circuit from one state to another and notifies state change
+// listeners and subscribers. Only the caller winning the transition updates the circuit,
+// so concurrent failures cannot extend the Open period and every transition is reported once.
+//
+// Entering Open stores the time the circuit opened and starts a new HalfOpen period.
+// Entering Closed clears the failure counters and the sliding window statistics.
+// Logs debug information on every transition (if logger is configured).
+//
+// Parameters:
+//   - ctx: Context passed for logging purposes
+//   - from: State the circuit is expected to be in
+//   - to: State the circuit moves to
+//   - err: Operation error that triggered the transition, or nil
+//
+// Returns:
+//   - bool: true if this call performed the transition
+func (cb *CircuitBreaker[T]) transition(ctx context.Context, from, to int32, err error) bool {
+	if !cb.state.CompareAndSwap(from, to) {
+		return false
+	}
+
+	now := cb.clock.Now()
+	metrics := cb.Metrics()
+
+	switch to {
+	case Open:
+		cb.lastFailureTime.Store(now)
+		cb.resetHalfOpen()
+	case Closed:
+		cb.clearCounters()
+	}
+
+	cb.notify(ctx, StateChange{
+		From:    from,
+		To:      to,
+		At:      now,
+		Metrics: metrics,
+		Err:     err,
+	})
+
+	return true
+}
+
+// notify logs the state change and delivers it to listeners and subscribers.
+//
+// Parameters:
+//   - ctx: Context passed for logging purposes
+//   - change: The state transition to report
+func (cb *CircuitBreaker[T]) notify(ctx context.Context, change StateChange) {
+	if cb.glogger != nil {
+		cb.glogger.Debug(
+			ctx,
+			"Gendure Circuit breaker action",
+			"type_name", cb.typeName,
+			"from", StateName(change.From),
+			"to", StateName(change.To),
+			"failure_count", change.Metrics.ConsecutiveFailures,
+			"failure_rate", change.Metrics.FailureRate,
+			"slow_call_rate", change.Metrics.SlowCallRate,
+		)
+	}
+
+	for _, listener := range cb.listeners {
+		listener(change)
+	}
+
+	cb.subscribers.publish(change)
+}
+
+// clearCounters resets the failure counter, the last failure time, the sliding window
+// statistics and the HalfOpen trial counters.
+func (cb *CircuitBreaker[T]) clearCounters() {
+	cb.failureCount.Store(0)
+	cb.lastFailureTime.Store(time.Time{})
+	cb.window.reset()
+	cb.resetHalfOpen()
+}
+
+// resetHalfOpen ends the current HalfOpen period and starts the next one with empty
+// trial request counters. Trial requests still in flight keep the old period and are ignored.
+func (cb *CircuitBreaker[T]) resetHalfOpen() {
+	period := cb.halfOpenPeriod.Add(1)
+
+	cb.halfOpenAdmitted.reset(period)
+	cb.halfOpenSuccesses.reset(period)
+	cb.halfOpenFailures.reset(period)
+}
+
 // GetState returns the current state of the circuit breaker.
 // Thread-safe and can be called concurrently.
 //
@@ -247,7 +921,7 @@ func (cb *circuitBreaker[T]) handleFailure(ctx context.Context) {
 //	if cb.GetState() == Open {
 //	    log.Println("Circuit is open, requests are being blocked")
 //	}
-func (cb *circuitBreaker[T]) GetState() int32 {
+func (cb *CircuitBreaker[T]) GetState() int32 {
 	return cb.state.Load()
 }
 
@@ -262,13 +936,65 @@ func (cb *circuitBreaker[T]) GetState() int32 {
 //
 //	failures := cb.GetCountFailure()
 //	log.Printf("Current failure count: %d", failures)
-func (cb *circuitBreaker[T]) GetCountFailure() int32 {
+func (cb *CircuitBreaker[T]) GetCountFailure() int32 {
 	return cb.failureCount.Load()
 }
 
+// Subscribe returns a channel receiving every subsequent state transition of the circuit breaker
+// and a function to cancel the subscription. Sends never block the protected calls: a transition
+// is dropped for a subscriber whose buffer is full.
+// Thread-safe and can be called concurrently.
+//
+// Parameters:
+//   - buffer: Capacity of the returned channel. Negative values are treated as 0.
+//
+// Returns:
+//   - <-chan StateChange: Channel receiving state transitions
+//   - func(): Cancels the subscription and closes the channel. Safe to call more than once.
+//
+// Example:
+//
+//	changes, unsubscribe := cb.Subscribe(16)
+//	defer unsubscribe()
+//
+//	go func() {
+//	    for change := range changes {
+//	        log.Printf("circuit %s -> %s", StateName(change.From), StateName(change.To))
+//	    }
+//	}()
+func (cb *CircuitBreaker[T]) Subscribe(buffer int) (<-chan StateChange, func()) {
+	return cb.subscribers.subscribe(buffer)
+}
+
+// Metrics returns a snapshot of the circuit breaker state and sliding window statistics.
+// Thread-safe and can be called concurrently.
+//
+// Returns:
+//   - CircuitBreakerMetrics: Current state, consecutive failures and window call statistics
+//
+// Example:
+//
+//	m := cb.Metrics()
+//	log.Printf("failure rate %.1f%% over %d calls", m.FailureRate, m.Calls)
+func (cb *CircuitBreaker[T]) Metrics() CircuitBreakerMetrics {
+	snapshot := cb.window.snapshot(cb.clock.Now())
+
+	return CircuitBreakerMetrics{
+		State:               cb.state.Load(),
+		ConsecutiveFailures: cb.failureCount.Load(),
+		Calls:               snapshot.calls,
+		FailedCalls:         snapshot.failures,
+		FailureRate:         snapshot.failureRate(),
+		SlowCalls:           snapshot.slowCalls,
+		SlowCallRate:        snapshot.slowCallRate(),
+		LastCallDuration:    time.Duration(cb.lastCallDuration.Load()),
+	}
+}
+
 // Reset manually resets the circuit breaker to Closed state.
-// Sets failure count to zero, transitions to Closed state, and clears last failure time.
-// Called automatically after successful operations.
+// Sets failure count to zero, transitions to Closed state, clears last failure time
+// and discards the sliding window statistics.
+// State change listeners and subscribers are notified when the circuit was not already Closed.
 // Thread-safe and can be called concurrently.
 //
 // Useful for:
@@ -280,8 +1006,17 @@ func (cb *circuitBreaker[T]) GetCountFailure() int32 {
 //
 //	// Manual reset after deployment or maintenance
 //	cb.Reset()
-func (cb *circuitBreaker[T]) Reset() {
-	cb.failureCount.Store(0)
-	cb.state.Store(Closed)
-	cb.lastFailureTime.Store(time.Time{})
+func (cb *CircuitBreaker[T]) Reset() {
+	for {
+		from := cb.state.Load()
+		if from == Closed {
+			cb.clearCounters()
+
+			return
+		}
+
+		if cb.transition(context.Background(), from, Closed, nil) {
+			return
+		}
+	}
 }
@@ -0,0 +1,65 @@
+package gendure
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen is matched by the error returned when the circuit rejects a call,
+	// either because it is Open or because every HalfOpen trial call is already in flight.
+	ErrCircuitOpen = errors.New("gendure: circuit breaker is open")
+
+	// ErrHalfOpenBusy is matched by the error returned when the circuit is HalfOpen and every
+	// permitted trial call is already in flight. It wraps ErrCircuitOpen, so a single
+	// errors.Is(err, ErrCircuitOpen) check covers every rejected call.
+	ErrHalfOpenBusy = fmt.Errorf("%w: half-open trial calls exhausted", ErrCircuitOpen)
+
+	// ErrOperationFailed is matched by the error returned when the protected operation failed
+	// and no fallback was supplied. The operation error is wrapped as well.
+	ErrOperationFailed = errors.New("gendure: circuit breaker operation failed")
+)
+
+// CircuitBreakerError is returned by Execute without fallback and by Do when the circuit
+// rejected a call or the operation failed. Use errors.Is with ErrCircuitOpen, ErrHalfOpenBusy
+// or ErrOperationFailed to tell the reasons apart, and errors.As to read RetryAfter.
+type CircuitBreakerError struct {
+	// Err is the reason: ErrCircuitOpen, ErrHalfOpenBusy or ErrOperationFailed.
+	Err error
+
+	// Cause is the error returned by the operation. Nil when the call was rejected.
+	Cause error
+
+	// State is the circuit breaker state when the call was rejected or failed.
+	State int32
+
+	// RetryAfter is the remaining time until the circuit admits trial calls again.
+	// Zero when unknown, such as when trial calls are already in flight or the operation failed.
+	RetryAfter time.Duration
+}
+
+// Error returns the reason, the Retry-After hint when known, and the operation error when present.
+func (e *CircuitBreakerError) Error() string {
+	message := e.Err.Error()
+
+	if e.RetryAfter > 0 {
+		message = fmt.Sprintf("%s (retry after %s)", message, e.RetryAfter)
+	}
+
+	if e.Cause != nil {
+		message = fmt.Sprintf("%s: %s", message, e.Cause)
+	}
+
+	return message
+}
+
+// Unwrap returns the reason and, when present, the operation error,
+// so errors.Is and errors.As match both.
+func (e *CircuitBreakerError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Err}
+	}
+
+	return []error{e.Err, e.Cause}
+}
@@ -0,0 +1,111 @@
+package gendure
+
+import (
+	"sync"
+	"time"
+)
+
+// StateChange describes a circuit breaker state transition.
+// Delivered to listeners registered with WithStateChangeListener and to channels
+// returned by Subscribe.
+type StateChange struct {
+	// From is the state the circuit breaker left (Closed, Open, or HalfOpen).
+	From int32
+
+	// To is the state the circuit breaker entered (Closed, Open, or HalfOpen).
+	To int32
+
+	// At is the time the transition happened.
+	At time.Time
+
+	// Metrics is the state and call statistics observed when the transition happened,
+	// before any counter was cleared by the new state.
+	Metrics CircuitBreakerMetrics
+
+	// Err is the operation error that triggered the transition.
+	// Nil when the transition was not caused by a failed call (recovery timeout,
+	// successful trial calls, slow calls or a manual Reset).
+	Err error
+}
+
+// StateName returns a human-readable name for a circuit breaker state.
+//
+// Parameters:
+//   - state: Circuit breaker state (Closed, Open, or HalfOpen)
+//
+// Returns:
+//   - string: "closed", "open", "half-open", or "unknown" for any other value
+//
+// Example:
+//
+//	log.Printf("circuit moved to %s", StateName(change.To))
+func StateName(state int32) string {
+	switch state {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// stateChangeSubscribers fans state changes out to subscribed channels.
+// Sends never block: a change is dropped for a subscriber whose buffer is full.
+type stateChangeSubscribers struct {
+	mu       sync.Mutex
+	channels []chan StateChange
+}
+
+// subscribe registers a new channel with the given buffer size.
+// Returns the channel and a function removing and closing it.
+func (s *stateChangeSubscribers) subscribe(buffer int) (<-chan StateChange, func()) {
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	channel := make(chan StateChange, buffer)
+
+	s.mu.Lock()
+	s.channels = append(s.channels, channel)
+	s.mu.Unlock()
+
+	var once sync.Once
+
+	return channel, func() {
+		once.Do(func() {
+			s.unsubscribe(channel)
+		})
+	}
+}
+
+// unsubscribe removes and closes the channel.
+func (s *stateChangeSubscribers) unsubscribe(channel chan StateChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, subscribed := range s.channels {
+		if subscribed == channel {
+			s.channels = append(s.channels[:i], s.channels[i+1:]...)
+
+			break
+		}
+	}
+
+	close(channel)
+}
+
+// publish delivers the change to every subscribed channel with buffer space available.
+func (s *stateChangeSubscribers) publish(change StateChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, channel := range s.channels {
+		select {
+		case channel <- change:
+		default:
+		}
+	}
+}
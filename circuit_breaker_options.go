@@ -0,0 +1,447 @@
+package gendure
+
+import "time"
+
+// CircuitBreakerOption configures a circuit breaker.
+// Options are applied in order; later options override earlier ones.
+// Every Option shared with retries, such as WithLogger, is also a CircuitBreakerOption.
+type CircuitBreakerOption interface {
+	applyCircuitBreaker(cfg *circuitBreakerConfig) error
+}
+
+// circuitBreakerOptionFunc adapts a function to the CircuitBreakerOption interface.
+type circuitBreakerOptionFunc func(cfg *circuitBreakerConfig) error
+
+func (f circuitBreakerOptionFunc) applyCircuitBreaker(cfg *circuitBreakerConfig) error {
+	return f(cfg)
+}
+
+// circuitBreakerConfig holds the settings collected from CircuitBreakerOption values.
+type circuitBreakerConfig struct {
+	sharedConfig
+
+	// failureThreshold is the number of consecutive failures that opens the circuit.
+	failureThreshold int32
+
+	// recoveryTimeout is the duration to wait before moving from Open to HalfOpen.
+	recoveryTimeout time.Duration
+
+	// windowSize is the number of most recent calls kept by the count-based sliding window.
+	windowSize int
+
+	// windowDuration is the span covered by the time-based sliding window.
+	// Zero selects the count-based window.
+	windowDuration time.Duration
+
+	// failureRateThreshold is the failure percentage (0-100] that opens the circuit.
+	// Zero disables failure-rate evaluation and keeps the consecutive failure mode.
+	failureRateThreshold float64
+
+	// minimumCalls is the number of calls the window must hold before rates are evaluated.
+	minimumCalls int
+
+	// slowCallDurationThreshold is the duration above which a call is considered slow.
+	// Zero disables slow call detection.
+	slowCallDurationThreshold time.Duration
+
+	// slowCallRateThreshold is the slow call percentage (0-100] that opens the circuit.
+	slowCallRateThreshold float64
+
+	// permittedHalfOpenCalls is the number of trial calls allowed in HalfOpen state.
+	permittedHalfOpenCalls int
+
+	// requiredHalfOpenSuccesses is the number of successful trial calls that closes the circuit.
+	requiredHalfOpenSuccesses int
+
+	// listeners are called on every state transition.
+	listeners []func(StateChange)
+
+	// classifier decides which operation errors count as failures.
+	classifier errorClassifier
+}
+
+// defaultCircuitBreakerConfig returns the configuration used when no options are supplied.
+func defaultCircuitBreakerConfig() circuitBreakerConfig {
+	return circuitBreakerConfig{
+		sharedConfig:              defaultSharedConfig(),
+		failureThreshold:          defaultFailureThreshold,
+		recoveryTimeout:           defaultRecoveryTimeout,
+		windowSize:                defaultSlidingWindowSize,
+		minimumCalls:              defaultMinimumCalls,
+		permittedHalfOpenCalls:    defaultPermittedHalfOpenCalls,
+		requiredHalfOpenSuccesses: defaultPermittedHalfOpenCalls,
+	}
+}
+
+// validate reports settings that are individually valid but inconsistent with each other.
+func (cfg circuitBreakerConfig) validate() error {
+	if cfg.windowDuration <= 0 && cfg.minimumCalls > cfg.windowSize {
+		return invalidOption(
+			"minimum calls (%d) cannot exceed the sliding window size (%d)",
+			cfg.minimumCalls, cfg.windowSize,
+		)
+	}
+
+	return nil
+}
+
+// newWindow creates the sliding window selected by the configuration.
+func (cfg circuitBreakerConfig) newWindow() slidingWindow {
+	if cfg.windowDuration > 0 {
+		return newTimeWindow(cfg.windowDuration)
+	}
+
+	return newCountWindow(cfg.windowSize)
+}
+
+// WithFailureThreshold sets the number of consecutive failures that opens the circuit.
+// Not used once WithFailureRateThreshold switches the breaker to failure-rate evaluation.
+//
+// Parameters:
+//   - threshold: Number of consecutive failures. Must be greater than 0. Defaults to 1.
+//
+// Example:
+//
+//	cb, err := NewCircuitBreakerWithOptions[string](WithFailureThreshold(5))
+func WithFailureThreshold(threshold int32) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if threshold <= 0 {
+			return invalidOption("failure threshold must be greater than 0, got %d", threshold)
+		}
+
+		cfg.failureThreshold = threshold
+
+		return nil
+	})
+}
+
+// WithRecoveryTimeout sets how long the circuit stays Open before admitting HalfOpen trial calls.
+//
+// Parameters:
+//   - timeout: Duration to wait before attempting recovery. Must be greater than 0.
+//     Defaults to 30 seconds.
+//
+// Example:
+//
+//	cb, err := NewCircuitBreakerWithOptions[string](WithRecoveryTimeout(time.Minute))
+func WithRecoveryTimeout(timeout time.Duration) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if timeout <= 0 {
+			return invalidOption("recovery timeout must be greater than 0, got %s", timeout)
+		}
+
+		cfg.recoveryTimeout = timeout
+
+		return nil
+	})
+}
+
+// WithCountSlidingWindow sets the size of the count-based sliding window used to compute
+// failure rates. The window always holds the outcomes of the last size calls.
+// Replaces a time-based window selected by a previous option.
+//
+// Parameters:
+//   - size: Number of most recent calls to aggregate. Must be greater than 0.
+//     Defaults to 100.
+//
+// Example:
+//
+//	cb := NewCircuitBreaker[string](5, 30*time.Second, nil,
+//	    WithCountSlidingWindow(50),
+//	    WithFailureRateThreshold(40),
+//	)
+func WithCountSlidingWindow(size int) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if size <= 0 {
+			return invalidOption("sliding window size must be greater than 0, got %d", size)
+		}
+
+		cfg.windowSize = size
+		cfg.windowDuration = 0
+
+		return nil
+	})
+}
+
+// WithTimeSlidingWindow replaces the count-based window with a time-based sliding window
+// that aggregates call outcomes into per-second buckets over the last size duration.
+// Suited for high-traffic services where the last N calls span only a few milliseconds.
+//
+// Parameters:
+//   - size: Span of the window, rounded up to whole seconds. Must be greater than 0.
+//
+// Example:
+//
+//	// Open when 50% of the calls made in the last 10 seconds failed
+//	cb := NewCircuitBreaker[int](1, 30*time.Second, nil,
+//	    WithTimeSlidingWindow(10*time.Second),
+//	    WithFailureRateThreshold(50),
+//	    WithMinimumCalls(100),
+//	)
+func WithTimeSlidingWindow(size time.Duration) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if size <= 0 {
+			return invalidOption("sliding window duration must be greater than 0, got %s", size)
+		}
+
+		cfg.windowDuration = size
+
+		return nil
+	})
+}
+
+// WithFailureRateThreshold switches the circuit breaker from consecutive failure counting
+// to failure-rate evaluation. The circuit opens when the percentage of failed calls in the
+// sliding window is greater than or equal to the threshold.
+//
+// When set, the consecutive failure threshold is no longer used to open the circuit;
+// successes no longer hide interleaved failures.
+//
+// Parameters:
+//   - threshold: Failure percentage in the range (0, 100].
+//
+// Example:
+//
+//	// Open when 50% or more of the last 20 calls failed
+//	cb := NewCircuitBreaker[int](1, 10*time.Second, nil,
+//	    WithCountSlidingWindow(20),
+//	    WithFailureRateThreshold(50),
+//	)
+func WithFailureRateThreshold(threshold float64) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if threshold <= 0 || threshold > percent {
+			return invalidOption("failure rate threshold must be in (0, 100], got %g", threshold)
+		}
+
+		cfg.failureRateThreshold = threshold
+
+		return nil
+	})
+}
+
+// WithMinimumCalls sets how many calls the sliding window must hold before the failure
+// rate is evaluated. Prevents the circuit from opening on the very first failures.
+//
+// Parameters:
+//   - calls: Minimum number of recorded calls. Must be greater than 0 and, with a
+//     count-based window, not greater than the window size. Defaults to 10.
+//
+// Example:
+//
+//	cb := NewCircuitBreaker[int](1, 10*time.Second, nil,
+//	    WithFailureRateThreshold(50),
+//	    WithMinimumCalls(20),
+//	)
+func WithMinimumCalls(calls int) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if calls <= 0 {
+			return invalidOption("minimum calls must be greater than 0, got %d", calls)
+		}
+
+		cfg.minimumCalls = calls
+
+		return nil
+	})
+}
+
+// WithSlowCallThreshold enables slow call detection. Calls taking longer than duration are
+// recorded as slow in the sliding window, whether they succeeded or failed, and the circuit
+// opens when the percentage of slow calls is greater than or equal to rateThreshold.
+// Slow call rates are evaluated once the window holds the minimum number of calls, in both
+// consecutive and failure-rate modes.
+//
+// Parameters:
+//   - duration: Call duration above which a call is slow. Must be greater than 0.
+//   - rateThreshold: Slow call percentage in the range (0, 100].
+//
+// Example:
+//
+//	// Open when 80% of the last 100 calls took longer than 2 seconds
+//	cb := NewCircuitBreaker[string](5, 30*time.Second, nil,
+//	    WithSlowCallThreshold(2*time.Second, 80),
+//	)
+func WithSlowCallThreshold(duration time.Duration, rateThreshold float64) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if duration <= 0 {
+			return invalidOption("slow call duration threshold must be greater than 0, got %s", duration)
+		}
+
+		if rateThreshold <= 0 || rateThreshold > percent {
+			return invalidOption("slow call rate threshold must be in (0, 100], got %g", rateThreshold)
+		}
+
+		cfg.slowCallDurationThreshold = duration
+		cfg.slowCallRateThreshold = rateThreshold
+
+		return nil
+	})
+}
+
+// WithHalfOpenCalls sets how many trial calls are admitted in HalfOpen state and how many of
+// them must succeed before the circuit closes. The circuit reopens as soon as enough trial
+// calls failed that the required successes can no longer be reached. When slow call detection
+// is enabled, a slow trial call counts as a failed one.
+//
+// Parameters:
+//   - permitted: Number of trial calls admitted while HalfOpen. Must be greater than 0.
+//     Defaults to 1.
+//   - requiredSuccesses: Number of successful trial calls that closes the circuit.
+//     Cannot exceed permitted. If <= 0, defaults to permitted (every trial call must succeed).
+//
+// Example:
+//
+//	// Admit 10 trial calls and close the circuit once 8 of them succeeded
+//	cb := NewCircuitBreaker[string](5, 30*time.Second, nil,
+//	    WithHalfOpenCalls(10, 8),
+//	)
+func WithHalfOpenCalls(permitted, requiredSuccesses int) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if permitted <= 0 {
+			return invalidOption("permitted half-open calls must be greater than 0, got %d", permitted)
+		}
+
+		if requiredSuccesses > permitted {
+			return invalidOption(
+				"required half-open successes (%d) cannot exceed permitted half-open calls (%d)",
+				requiredSuccesses, permitted,
+			)
+		}
+
+		if requiredSuccesses <= 0 {
+			requiredSuccesses = permitted
+		}
+
+		cfg.permittedHalfOpenCalls = permitted
+		cfg.requiredHalfOpenSuccesses = requiredSuccesses
+
+		return nil
+	})
+}
+
+// WithStateChangeListener registers a function called on every state transition:
+// Closed to Open, Open to HalfOpen, HalfOpen to Open and HalfOpen to Closed, including
+// transitions caused by Reset. The option can be repeated to register several listeners.
+//
+// Listeners run synchronously in the goroutine that caused the transition and must return
+// quickly; hand long-running work such as paging to another goroutine, or use Subscribe.
+//
+// Parameters:
+//   - listener: Function receiving the state transition. Cannot be nil.
+//
+// Example:
+//
+//	cb := NewCircuitBreaker[string](5, 30*time.Second, nil,
+//	    WithStateChangeListener(func(change StateChange) {
+//	        stateGauge.Set(float64(change.To))
+//	    }),
+//	)
+func WithStateChangeListener(listener func(StateChange)) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if listener == nil {
+			return invalidOption("state change listener cannot be nil")
+		}
+
+		cfg.listeners = append(cfg.listeners, listener)
+
+		return nil
+	})
+}
+
+// WithIgnoredErrors makes the circuit breaker ignore errors matching any of errs with errors.Is.
+// Ignored errors count neither as successes nor as failures and are returned to the caller
+// without calling the fallback; a HalfOpen trial call ending with one frees its trial slot.
+// Typical candidates are context.Canceled from the caller or client-side validation errors.
+//
+// Parameters:
+//   - errs: Errors to ignore. Nil errors are skipped.
+//
+// Example:
+//
+//	cb := NewCircuitBreaker[string](5, 30*time.Second, nil,
+//	    WithIgnoredErrors(context.Canceled, ErrInvalidInput),
+//	)
+func WithIgnoredErrors(errs ...error) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		cfg.classifier.ignored = appendNonNil(cfg.classifier.ignored, errs)
+
+		return nil
+	})
+}
+
+// WithIgnoreErrorIf makes the circuit breaker ignore errors for which predicate returns true.
+// Behaves like WithIgnoredErrors for errors that cannot be matched by value, for instance
+// with IsErrorType to match by type. The option can be repeated.
+//
+// Parameters:
+//   - predicate: Reports whether an error is ignored. Cannot be nil.
+//
+// Example:
+//
+//	cb := NewCircuitBreaker[string](5, 30*time.Second, nil,
+//	    WithIgnoreErrorIf(IsErrorType[*ValidationError]()),
+//	)
+func WithIgnoreErrorIf(predicate func(err error) bool) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		if predicate == nil {
+			return invalidOption("ignore error predicate cannot be nil")
+		}
+
+		cfg.classifier.ignoreIf = append(cfg.classifier.ignoreIf, predicate)
+
+		return nil
+	})
+}
+
+// WithSuccessErrors makes errors matching any of errs with errors.Is count as successful calls.
+// The error is still returned to the caller without calling the fallback, but it proves the
+// service is healthy, such as a 404-style business error.
+//
+// Parameters:
+//   - errs: Errors counted as successes. Nil errors are skipped.
+//
+// Example:
+//
+//	cb := NewCircuitBreaker[*User](5, 30*time.Second, nil,
+//	    WithSuccessErrors(ErrUserNotFound),
+//	)
+func WithSuccessErrors(errs ...error) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		cfg.classifier.successes = appendNonNil(cfg.classifier.successes, errs)
+
+		return nil
+	})
+}
+
+// WithIsFailure sets the predicate deciding which errors trip the circuit breaker.
+// Errors for which predicate returns false count as successful calls and are returned
+// to the caller without calling the fallback. Ignore and success rules are checked first.
+//
+// Parameters:
+//   - predicate: Reports whether an error is a failure. If nil, every error is a failure.
+//
+// Example:
+//
+//	cb := NewCircuitBreaker[*http.Response](5, 30*time.Second, nil,
+//	    WithIsFailure(func(err error) bool {
+//	        var status *StatusError
+//	        return !errors.As(err, &status) || status.Code >= 500
+//	    }),
+//	)
+func WithIsFailure(predicate func(err error) bool) CircuitBreakerOption {
+	return circuitBreakerOptionFunc(func(cfg *circuitBreakerConfig) error {
+		cfg.classifier.isFailure = predicate
+
+		return nil
+	})
+}
+
+// appendNonNil appends the non-nil errors of errs to dst.
+func appendNonNil(dst, errs []error) []error {
+	for _, err := range errs {
+		if err != nil {
+			dst = append(dst, err)
+		}
+	}
+
+	return dst
+}
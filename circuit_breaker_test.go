@@ -4,12 +4,14 @@ package gendure_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/marincor/gendure"
+	"github.com/marincor/gendure/clocktest"
 )
 
 var (
@@ -187,3 +189,1023 @@ func TestCircuitBreakerRaceCondition(t *testing.T) {
 		t.Errorf("expected failure count to be less than %d, got %d", failureThreshold, failureCount)
 	}
 }
+
+func TestCircuitBreakerFailureRateOpensWithInterleavedSuccesses(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		3,
+		1*time.Second,
+		nil,
+		gendure.WithCountSlidingWindow(10),
+		gendure.WithFailureRateThreshold(40),
+		gendure.WithMinimumCalls(10),
+	)
+
+	fallback := func() (int, error) {
+		return -1, nil
+	}
+
+	// 40% of calls fail, but never consecutively
+	for i := 0; i < 10; i++ {
+		fail := i%5 == 1 || i%5 == 3
+
+		_, _ = cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				if fail {
+					return 0, errOperation
+				}
+
+				return 1, nil
+			},
+			fallback,
+		)
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open, got %d", state)
+	}
+
+	metrics := cirbuitBreaker.Metrics()
+	if metrics.Calls != 10 || metrics.FailedCalls != 4 {
+		t.Errorf("expected 4 failures over 10 calls, got %d over %d", metrics.FailedCalls, metrics.Calls)
+	}
+
+	if metrics.FailureRate != 40 {
+		t.Errorf("expected failure rate 40, got %f", metrics.FailureRate)
+	}
+}
+
+func TestCircuitBreakerFailureRateWaitsForMinimumCalls(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		1,
+		1*time.Second,
+		nil,
+		gendure.WithCountSlidingWindow(10),
+		gendure.WithFailureRateThreshold(50),
+		gendure.WithMinimumCalls(5),
+	)
+
+	for i := 0; i < 4; i++ {
+		_, _ = cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				return 0, errOperation
+			},
+			func() (int, error) {
+				return -1, nil
+			},
+		)
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to be Closed before minimum calls, got %d", state)
+	}
+
+	_, _ = cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			return 0, errOperation
+		},
+		func() (int, error) {
+			return -1, nil
+		},
+	)
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open, got %d", state)
+	}
+}
+
+func TestCircuitBreakerCountWindowEvictsOldestCalls(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		1,
+		1*time.Second,
+		nil,
+		gendure.WithCountSlidingWindow(4),
+		gendure.WithFailureRateThreshold(100),
+		gendure.WithMinimumCalls(4),
+	)
+
+	outcomes := []error{errOperation, errOperation, nil, nil, nil, nil}
+	for _, outcome := range outcomes {
+		_, _ = cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				return 0, outcome
+			},
+			func() (int, error) {
+				return -1, nil
+			},
+		)
+	}
+
+	metrics := cirbuitBreaker.Metrics()
+	if metrics.Calls != 4 || metrics.FailedCalls != 0 {
+		t.Errorf("expected 0 failures over 4 calls, got %d over %d", metrics.FailedCalls, metrics.Calls)
+	}
+}
+
+func TestCircuitBreakerTimeWindowFailureRateOpens(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		1,
+		1*time.Second,
+		nil,
+		gendure.WithTimeSlidingWindow(10*time.Second),
+		gendure.WithFailureRateThreshold(50),
+		gendure.WithMinimumCalls(4),
+	)
+
+	outcomes := []error{nil, errOperation, nil, errOperation}
+	for _, outcome := range outcomes {
+		_, _ = cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				return 0, outcome
+			},
+			func() (int, error) {
+				return -1, nil
+			},
+		)
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open, got %d", state)
+	}
+
+	if metrics := cirbuitBreaker.Metrics(); metrics.Calls != 4 || metrics.FailureRate != 50 {
+		t.Errorf("expected failure rate 50 over 4 calls, got %f over %d", metrics.FailureRate, metrics.Calls)
+	}
+}
+
+func TestCircuitBreakerSlowCallRateOpens(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		3,
+		1*time.Second,
+		nil,
+		gendure.WithCountSlidingWindow(4),
+		gendure.WithMinimumCalls(4),
+		gendure.WithSlowCallThreshold(5*time.Millisecond, 50),
+	)
+
+	delays := []time.Duration{0, 10 * time.Millisecond, 0, 10 * time.Millisecond}
+	for _, delay := range delays {
+		result, err := cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				time.Sleep(delay)
+
+				return 42, nil
+			},
+			func() (int, error) {
+				return 0, errFallback
+			},
+		)
+		if err != nil || result != 42 {
+			t.Errorf("expected slow call to succeed, got %d, %v", result, err)
+		}
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open, got %d", state)
+	}
+
+	metrics := cirbuitBreaker.Metrics()
+	if metrics.SlowCalls != 2 || metrics.SlowCallRate != 50 {
+		t.Errorf("expected 2 slow calls at rate 50, got %d at %f", metrics.SlowCalls, metrics.SlowCallRate)
+	}
+
+	if metrics.LastCallDuration < 10*time.Millisecond {
+		t.Errorf("expected last call duration of at least 10ms, got %s", metrics.LastCallDuration)
+	}
+}
+
+func TestCircuitBreakerHalfOpenRequiresTrialSuccesses(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		1,
+		50*time.Millisecond,
+		nil,
+		gendure.WithHalfOpenCalls(3, 2),
+	)
+
+	succeed := func() (int, error) {
+		return 42, nil
+	}
+	fail := func() (int, error) {
+		return 0, errOperation
+	}
+	fallback := func() (int, error) {
+		return -1, nil
+	}
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, fallback)
+	time.Sleep(60 * time.Millisecond)
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, fallback)
+	if state := cirbuitBreaker.GetState(); state != gendure.HalfOpen {
+		t.Errorf("expected state to stay HalfOpen after one failed trial, got %d", state)
+	}
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), succeed, fallback)
+	if state := cirbuitBreaker.GetState(); state != gendure.HalfOpen {
+		t.Errorf("expected state to stay HalfOpen after one successful trial, got %d", state)
+	}
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), succeed, fallback)
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to be Closed after two successful trials, got %d", state)
+	}
+}
+
+func TestCircuitBreakerHalfOpenReopensWhenSuccessesUnreachable(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		1,
+		50*time.Millisecond,
+		nil,
+		gendure.WithHalfOpenCalls(3, 2),
+	)
+
+	fail := func() (int, error) {
+		return 0, errOperation
+	}
+	fallback := func() (int, error) {
+		return -1, nil
+	}
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, fallback)
+	time.Sleep(60 * time.Millisecond)
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, fallback)
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, fallback)
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open after two failed trials, got %d", state)
+	}
+}
+
+func TestCircuitBreakerHalfOpenRejectsCallsBeyondPermitted(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, 50*time.Millisecond, nil)
+
+	_, _ = cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			return 0, errOperation
+		},
+		func() (int, error) {
+			return -1, nil
+		},
+	)
+	time.Sleep(60 * time.Millisecond)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				close(started)
+				<-release
+
+				return 42, nil
+			},
+			func() (int, error) {
+				return -1, nil
+			},
+		)
+	}()
+
+	<-started
+
+	result, _ := cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			t.Error("should not call operation when trial calls are exhausted")
+
+			return 0, nil
+		},
+		func() (int, error) {
+			return 99, nil
+		},
+	)
+	if result != 99 {
+		t.Errorf("expected 99, got %d", result)
+	}
+
+	close(release)
+	<-done
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to be Closed, got %d", state)
+	}
+}
+
+func TestCircuitBreakerNotifiesStateChanges(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		changes []gendure.StateChange
+	)
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		2,
+		50*time.Millisecond,
+		nil,
+		gendure.WithStateChangeListener(func(change gendure.StateChange) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			changes = append(changes, change)
+		}),
+	)
+
+	subscription, unsubscribe := cirbuitBreaker.Subscribe(4)
+
+	fail := func() (int, error) {
+		return 0, errOperation
+	}
+	fallback := func() (int, error) {
+		return -1, nil
+	}
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, fallback)
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, fallback)
+	time.Sleep(60 * time.Millisecond)
+	_, _ = cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			return 42, nil
+		},
+		fallback,
+	)
+
+	expected := [][2]int32{
+		{gendure.Closed, gendure.Open},
+		{gendure.Open, gendure.HalfOpen},
+		{gendure.HalfOpen, gendure.Closed},
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d state changes, got %d", len(expected), len(changes))
+	}
+
+	for i, change := range changes {
+		if change.From != expected[i][0] || change.To != expected[i][1] {
+			t.Errorf("expected change %d to be %s -> %s, got %s -> %s", i,
+				gendure.StateName(expected[i][0]), gendure.StateName(expected[i][1]),
+				gendure.StateName(change.From), gendure.StateName(change.To))
+		}
+	}
+
+	if !errors.Is(changes[0].Err, errOperation) {
+		t.Errorf("expected opening change to carry the operation error, got %v", changes[0].Err)
+	}
+
+	if changes[0].Metrics.ConsecutiveFailures != 2 {
+		t.Errorf("expected opening change to report 2 failures, got %d", changes[0].Metrics.ConsecutiveFailures)
+	}
+
+	for i := range expected {
+		change := <-subscription
+		if change.To != expected[i][1] {
+			t.Errorf("expected subscribed change %d to enter %s, got %s", i,
+				gendure.StateName(expected[i][1]), gendure.StateName(change.To))
+		}
+	}
+
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-subscription; ok {
+		t.Error("expected subscription channel to be closed")
+	}
+}
+
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return "status error"
+}
+
+func TestCircuitBreakerIgnoredErrorsDoNotTrip(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		1,
+		1*time.Second,
+		nil,
+		gendure.WithIgnoredErrors(context.Canceled),
+		gendure.WithIgnoreErrorIf(gendure.IsErrorType[*statusError]()),
+	)
+
+	ignored := []error{
+		context.Canceled,
+		fmt.Errorf("wrapped: %w", context.Canceled),
+		&statusError{code: 400},
+	}
+
+	for _, ignoredErr := range ignored {
+		result, err := cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				return 7, ignoredErr
+			},
+			func() (int, error) {
+				t.Error("should not call fallback for an ignored error")
+
+				return 0, nil
+			},
+		)
+		if !errors.Is(err, ignoredErr) || result != 7 {
+			t.Errorf("expected operation result and error, got %d, %v", result, err)
+		}
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to be Closed, got %d", state)
+	}
+
+	if metrics := cirbuitBreaker.Metrics(); metrics.Calls != 0 {
+		t.Errorf("expected ignored errors not to be recorded, got %d calls", metrics.Calls)
+	}
+}
+
+func TestCircuitBreakerSuccessErrorsCountAsSuccess(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("not found")
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		2,
+		1*time.Second,
+		nil,
+		gendure.WithSuccessErrors(errNotFound),
+		gendure.WithIsFailure(func(err error) bool {
+			var status *statusError
+
+			return !errors.As(err, &status) || status.code >= 500
+		}),
+	)
+
+	outcomes := []error{errOperation, errNotFound, errOperation, &statusError{code: 404}, errOperation}
+	for _, outcome := range outcomes {
+		_, _ = cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				return 0, outcome
+			},
+			func() (int, error) {
+				return -1, nil
+			},
+		)
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to be Closed, got %d", state)
+	}
+
+	metrics := cirbuitBreaker.Metrics()
+	if metrics.Calls != 5 || metrics.FailedCalls != 3 {
+		t.Errorf("expected 3 failures over 5 calls, got %d over %d", metrics.FailedCalls, metrics.Calls)
+	}
+
+	_, _ = cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			return 0, &statusError{code: 503}
+		},
+		func() (int, error) {
+			return -1, nil
+		},
+	)
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open, got %d", state)
+	}
+}
+
+func TestCircuitBreakerExecuteWithoutFallbackReturnsTypedErrors(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, 50*time.Millisecond, nil)
+
+	_, err := cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			return 0, errOperation
+		},
+		nil,
+	)
+	if !errors.Is(err, gendure.ErrOperationFailed) || !errors.Is(err, errOperation) {
+		t.Errorf("expected ErrOperationFailed wrapping the operation error, got %v", err)
+	}
+
+	if errors.Is(err, gendure.ErrCircuitOpen) {
+		t.Errorf("expected operation failure not to match ErrCircuitOpen, got %v", err)
+	}
+
+	_, err = cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			return 42, nil
+		},
+		nil,
+	)
+	if !errors.Is(err, gendure.ErrCircuitOpen) || errors.Is(err, gendure.ErrHalfOpenBusy) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	var cbErr *gendure.CircuitBreakerError
+	if !errors.As(err, &cbErr) {
+		t.Fatalf("expected *CircuitBreakerError, got %T", err)
+	}
+
+	if cbErr.State != gendure.Open || cbErr.RetryAfter <= 0 || cbErr.RetryAfter > 50*time.Millisecond {
+		t.Errorf("expected Open state with retry after in (0, 50ms], got %d and %s", cbErr.State, cbErr.RetryAfter)
+	}
+}
+
+func TestCircuitBreakerExecuteWithoutFallbackReturnsHalfOpenBusy(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, 50*time.Millisecond, nil)
+
+	_, _ = cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			return 0, errOperation
+		},
+		nil,
+	)
+	time.Sleep(60 * time.Millisecond)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				close(started)
+				<-release
+
+				return 42, nil
+			},
+			nil,
+		)
+	}()
+
+	<-started
+
+	_, err := cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			return 42, nil
+		},
+		nil,
+	)
+	if !errors.Is(err, gendure.ErrHalfOpenBusy) || !errors.Is(err, gendure.ErrCircuitOpen) {
+		t.Errorf("expected ErrHalfOpenBusy matching ErrCircuitOpen, got %v", err)
+	}
+
+	close(release)
+	<-done
+}
+
+func TestCircuitBreakerExecuteWithoutFallbackReturnsContextError(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, time.Second, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := cirbuitBreaker.Execute(
+		ctx,
+		func() (int, error) {
+			return 42, nil
+		},
+		nil,
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCircuitBreakerExecuteWithFallbackReceivesCause(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, time.Second, nil)
+
+	var causes []error
+
+	fallback := func(ctx context.Context, cause error) (int, error) {
+		causes = append(causes, cause)
+
+		return -1, nil
+	}
+	fail := func() (int, error) {
+		return 0, errOperation
+	}
+
+	result, err := cirbuitBreaker.ExecuteWithFallback(context.Background(), fail, fallback)
+	if err != nil || result != -1 {
+		t.Errorf("expected fallback result -1 without error, got %d and %v", result, err)
+	}
+
+	_, _ = cirbuitBreaker.ExecuteWithFallback(context.Background(), fail, fallback)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _ = cirbuitBreaker.ExecuteWithFallback(ctx, fail, fallback)
+
+	if len(causes) != 3 {
+		t.Fatalf("expected 3 fallback calls, got %d", len(causes))
+	}
+
+	if !errors.Is(causes[0], gendure.ErrOperationFailed) || !errors.Is(causes[0], errOperation) {
+		t.Errorf("expected operation failure cause, got %v", causes[0])
+	}
+
+	if !errors.Is(causes[1], gendure.ErrCircuitOpen) {
+		t.Errorf("expected circuit open cause, got %v", causes[1])
+	}
+
+	if !errors.Is(causes[2], context.Canceled) {
+		t.Errorf("expected context cancelled cause, got %v", causes[2])
+	}
+}
+
+func TestCircuitBreakerExecuteContextHonorsCancellationMidCall(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, time.Second, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var cause error
+
+	result, err := cirbuitBreaker.ExecuteContext(
+		ctx,
+		func(ctx context.Context) (int, error) {
+			cancel()
+			<-ctx.Done()
+
+			return 0, fmt.Errorf("request aborted: %w", ctx.Err())
+		},
+		func(ctx context.Context, err error) (int, error) {
+			cause = err
+
+			return -1, nil
+		},
+	)
+	if err != nil || result != -1 {
+		t.Errorf("expected fallback result -1 without error, got %d and %v", result, err)
+	}
+
+	if !errors.Is(cause, context.Canceled) {
+		t.Errorf("expected context.Canceled cause, got %v", cause)
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to stay Closed, got %d", state)
+	}
+
+	if metrics := cirbuitBreaker.Metrics(); metrics.Calls != 0 || metrics.ConsecutiveFailures != 0 {
+		t.Errorf("expected cancelled call not to be recorded, got %d calls and %d failures",
+			metrics.Calls, metrics.ConsecutiveFailures)
+	}
+}
+
+func TestCircuitBreakerCountsDeadlineExceededAsFailure(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, time.Minute, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := cirbuitBreaker.Execute(
+		ctx,
+		func() (int, error) {
+			<-ctx.Done()
+
+			return 0, ctx.Err()
+		},
+		nil,
+	)
+	if !errors.Is(err, gendure.ErrOperationFailed) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected ErrOperationFailed wrapping context.DeadlineExceeded, got %v", err)
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open after a timed out call, got %d", state)
+	}
+
+	if count := cirbuitBreaker.GetCountFailure(); count != 1 {
+		t.Errorf("expected 1 failure, got %d", count)
+	}
+}
+
+func TestCircuitBreakerRecordsPanicBeforePropagating(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, 50*time.Millisecond, nil)
+
+	fail := func() (int, error) {
+		return 0, errOperation
+	}
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, nil)
+	time.Sleep(60 * time.Millisecond)
+
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Errorf("expected the original panic value, got %v", recovered)
+			}
+		}()
+
+		_, _ = cirbuitBreaker.Execute(
+			context.Background(),
+			func() (int, error) {
+				panic("boom")
+			},
+			nil,
+		)
+	}()
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected panicking trial to reopen the circuit, got %d", state)
+	}
+}
+
+func TestCircuitBreakerPanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		1,
+		time.Second,
+		nil,
+		gendure.WithPanicRecovery(false),
+		gendure.WithIgnoredErrors(errOperation),
+	)
+
+	_, err := cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			panic(errOperation)
+		},
+		nil,
+	)
+
+	var panicErr *gendure.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+
+	if panicErr.Value != errOperation || len(panicErr.Stack) == 0 {
+		t.Errorf("expected panic value and stack, got %v and %d bytes", panicErr.Value, len(panicErr.Stack))
+	}
+
+	if !errors.Is(err, gendure.ErrOperationFailed) || !errors.Is(err, errOperation) {
+		t.Errorf("expected operation failure wrapping the panic value, got %v", err)
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected panic to count as a failure regardless of classification, got %d", state)
+	}
+}
+
+func TestCircuitBreakerPanicRecoveryRepanics(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](1, time.Second, nil, gendure.WithPanicRecovery(true))
+
+	defer func() {
+		if _, ok := recover().(*gendure.PanicError); !ok {
+			t.Errorf("expected re-panic with *PanicError")
+		}
+
+		if state := cirbuitBreaker.GetState(); state != gendure.Open {
+			t.Errorf("expected panic to be accounted before re-panicking, got %d", state)
+		}
+	}()
+
+	_, _ = cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			panic("boom")
+		},
+		nil,
+	)
+}
+
+func TestCircuitBreakerRecoveryTimeoutWithClock(t *testing.T) {
+	t.Parallel()
+
+	clock := clocktest.NewClock(time.Unix(0, 0))
+
+	cirbuitBreaker, err := gendure.NewCircuitBreakerWithOptions[int](
+		gendure.WithRecoveryTimeout(time.Minute),
+		gendure.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	_, _ = cirbuitBreaker.Execute(
+		context.Background(),
+		func() (int, error) {
+			clock.Advance(2 * time.Second)
+
+			return 0, errOperation
+		},
+		nil,
+	)
+
+	if duration := cirbuitBreaker.Metrics().LastCallDuration; duration != 2*time.Second {
+		t.Errorf("expected call duration of 2s, got %s", duration)
+	}
+
+	clock.Advance(45 * time.Second)
+
+	var cbErr *gendure.CircuitBreakerError
+
+	_, err = cirbuitBreaker.Execute(context.Background(), func() (int, error) { return 42, nil }, nil)
+	if !errors.As(err, &cbErr) || cbErr.RetryAfter != 15*time.Second {
+		t.Errorf("expected rejection with 15s retry after, got %v", err)
+	}
+
+	clock.Advance(16 * time.Second)
+
+	result, err := cirbuitBreaker.Execute(context.Background(), func() (int, error) { return 42, nil }, nil)
+	if err != nil || result != 42 {
+		t.Errorf("expected trial call to succeed, got %d and %v", result, err)
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to be Closed, got %d", state)
+	}
+}
+
+// startBlockedTrial runs a call that blocks until release is closed, returning once it was admitted.
+func startBlockedTrial(
+	ctx context.Context,
+	cirbuitBreaker *gendure.CircuitBreaker[int],
+	release <-chan struct{},
+	result int,
+	err error,
+) <-chan struct{} {
+	started := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = cirbuitBreaker.Do(ctx, func(ctx context.Context) (int, error) {
+			close(started)
+
+			select {
+			case <-release:
+				return result, err
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		})
+	}()
+
+	<-started
+
+	return done
+}
+
+func newHalfOpenClockBreaker(t *testing.T, clock *clocktest.Clock) *gendure.CircuitBreaker[int] {
+	t.Helper()
+
+	cirbuitBreaker, err := gendure.NewCircuitBreakerWithOptions[int](
+		gendure.WithRecoveryTimeout(time.Second),
+		gendure.WithHalfOpenCalls(3, 2),
+		gendure.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	return cirbuitBreaker
+}
+
+func TestCircuitBreakerHalfOpenIgnoresSuccessFromEndedPeriod(t *testing.T) {
+	t.Parallel()
+
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	cirbuitBreaker := newHalfOpenClockBreaker(t, clock)
+
+	succeed := func() (int, error) { return 42, nil }
+	fail := func() (int, error) { return 0, errOperation }
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, nil)
+	clock.Advance(2 * time.Second)
+
+	// Trial C is still in flight when trials A and B reopen the circuit
+	release := make(chan struct{})
+	done := startBlockedTrial(context.Background(), cirbuitBreaker, release, 42, nil)
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, nil)
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, nil)
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Fatalf("expected state to be Open after two failed trials, got %d", state)
+	}
+
+	close(release)
+	<-done
+
+	clock.Advance(2 * time.Second)
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), succeed, nil)
+	if state := cirbuitBreaker.GetState(); state != gendure.HalfOpen {
+		t.Errorf("expected state to stay HalfOpen after one successful trial of the new period, got %d", state)
+	}
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), succeed, nil)
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to be Closed after two successful trials, got %d", state)
+	}
+}
+
+func TestCircuitBreakerHalfOpenIgnoredTrialFromEndedPeriodKeepsPermits(t *testing.T) {
+	t.Parallel()
+
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	cirbuitBreaker := newHalfOpenClockBreaker(t, clock)
+
+	fail := func() (int, error) { return 0, errOperation }
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, nil)
+	clock.Advance(2 * time.Second)
+
+	// Trial C is still in flight when trials A and B reopen the circuit
+	ctx, cancel := context.WithCancel(context.Background())
+	stale := startBlockedTrial(ctx, cirbuitBreaker, nil, 0, nil)
+
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, nil)
+	_, _ = cirbuitBreaker.Execute(context.Background(), fail, nil)
+
+	clock.Advance(2 * time.Second)
+
+	// Every permitted trial of the new period is in flight
+	release := make(chan struct{})
+	trials := make([]<-chan struct{}, 0, 3)
+
+	for range 3 {
+		trials = append(trials, startBlockedTrial(context.Background(), cirbuitBreaker, release, 42, nil))
+	}
+
+	// Cancelling the stale trial must not release a permit of the new period
+	cancel()
+	<-stale
+
+	_, err := cirbuitBreaker.Execute(context.Background(), func() (int, error) { return 42, nil }, nil)
+	if !errors.Is(err, gendure.ErrHalfOpenBusy) {
+		t.Errorf("expected ErrHalfOpenBusy beyond the permitted trials, got %v", err)
+	}
+
+	close(release)
+
+	for _, done := range trials {
+		<-done
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Closed {
+		t.Errorf("expected state to be Closed after the permitted trials succeeded, got %d", state)
+	}
+}
@@ -0,0 +1,55 @@
+package gendure
+
+import "time"
+
+// Clock provides the current time and timers to circuit breakers and retries.
+// The default implementation uses the time package; tests can inject a manual clock,
+// such as the one in the clocktest package, to control time deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a Timer that sends the current time on its channel after at least d.
+	NewTimer(d time.Duration) Timer
+
+	// After waits for d to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Timer is a single event scheduled by a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing.
+	// Returns false if the timer already fired or was stopped.
+	Stop() bool
+}
+
+// realClock implements Clock with the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// realTimer implements Timer with a *time.Timer.
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
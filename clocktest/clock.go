@@ -0,0 +1,195 @@
+// Package clocktest provides a manual gendure.Clock for deterministic tests.
+// Time only moves when Advance or Set is called, firing every timer that became due.
+package clocktest
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/marincor/gendure"
+)
+
+// Clock is a manual gendure.Clock. The zero value is not usable; create one with NewClock.
+// It is safe for concurrent use.
+type Clock struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	timers  []*timer
+}
+
+// compile-time check that *Clock implements gendure.Clock.
+var _ gendure.Clock = (*Clock)(nil)
+
+// NewClock returns a manual clock set to start.
+//
+// Parameters:
+//   - start: The initial time reported by Now
+//
+// Returns:
+//   - *Clock: A clock that only moves when advanced
+//
+// Example:
+//
+//	clock := clocktest.NewClock(time.Unix(0, 0))
+//	cb, err := gendure.NewCircuitBreakerWithOptions[string](gendure.WithClock(clock))
+func NewClock(start time.Time) *Clock {
+	clock := &Clock{now: start}
+	clock.changed = sync.NewCond(&clock.mu)
+
+	return clock
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer schedules a timer firing once the clock is advanced by at least d.
+// A timer with d <= 0 fires immediately.
+func (c *Clock) NewTimer(d time.Duration) gendure.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &timer{clock: c, deadline: c.now.Add(d), channel: make(chan time.Time, 1)}
+
+	if d <= 0 {
+		t.channel <- c.now
+
+		return t
+	}
+
+	c.timers = append(c.timers, t)
+	c.changed.Broadcast()
+
+	return t
+}
+
+// After returns a channel receiving the clock time once the clock is advanced by at least d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Advance moves the clock forward by d and fires every timer that became due, in deadline order.
+//
+// Parameters:
+//   - d: Duration to move forward. Negative values are ignored.
+func (c *Clock) Advance(d time.Duration) {
+	if d < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(c.now.Add(d))
+}
+
+// Set moves the clock to t and fires every timer that became due, in deadline order.
+// Times before the current time are ignored: the clock never goes backwards.
+//
+// Parameters:
+//   - t: The new current time
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.Before(c.now) {
+		return
+	}
+
+	c.set(t)
+}
+
+// set moves the clock to t and fires due timers. Must be called with mu held.
+func (c *Clock) set(t time.Time) {
+	c.now = t
+
+	slices.SortStableFunc(c.timers, func(a, b *timer) int {
+		return a.deadline.Compare(b.deadline)
+	})
+
+	pending := c.timers[:0]
+
+	for _, scheduled := range c.timers {
+		if scheduled.deadline.After(t) {
+			pending = append(pending, scheduled)
+
+			continue
+		}
+
+		scheduled.channel <- t
+	}
+
+	clear(c.timers[len(pending):])
+	c.timers = pending
+	c.changed.Broadcast()
+}
+
+// Timers returns the deadlines of the pending timers, earliest first.
+//
+// Returns:
+//   - []time.Time: Deadline of every timer neither fired nor stopped
+func (c *Clock) Timers() []time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deadlines := make([]time.Time, 0, len(c.timers))
+	for _, scheduled := range c.timers {
+		deadlines = append(deadlines, scheduled.deadline)
+	}
+
+	slices.SortFunc(deadlines, time.Time.Compare)
+
+	return deadlines
+}
+
+// BlockUntilTimers blocks until at least n timers are pending. Use it to wait for code running
+// in another goroutine, such as a retry sleeping between attempts, before advancing the clock.
+//
+// Parameters:
+//   - n: Number of pending timers to wait for
+func (c *Clock) BlockUntilTimers(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.timers) < n {
+		c.changed.Wait()
+	}
+}
+
+// remove unschedules t. Returns false if it already fired or was stopped.
+func (c *Clock) remove(t *timer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, scheduled := range c.timers {
+		if scheduled == t {
+			c.timers = slices.Delete(c.timers, i, i+1)
+			c.changed.Broadcast()
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// timer is a gendure.Timer scheduled on a manual Clock.
+type timer struct {
+	clock    *Clock
+	deadline time.Time
+	channel  chan time.Time
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.channel
+}
+
+func (t *timer) Stop() bool {
+	return t.clock.remove(t)
+}
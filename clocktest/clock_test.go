@@ -0,0 +1,83 @@
+//nolint:all // only test
+package clocktest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marincor/gendure/clocktest"
+)
+
+func TestClockAdvanceFiresDueTimers(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	clock := clocktest.NewClock(start)
+
+	first := clock.NewTimer(time.Second)
+	second := clock.After(2 * time.Second)
+
+	if deadlines := clock.Timers(); len(deadlines) != 2 || !deadlines[0].Equal(start.Add(time.Second)) {
+		t.Fatalf("expected 2 pending timers, first due at 1s, got %v", deadlines)
+	}
+
+	clock.Advance(time.Second)
+
+	select {
+	case fired := <-first.C():
+		if !fired.Equal(start.Add(time.Second)) {
+			t.Errorf("expected timer to fire at 1s, got %v", fired)
+		}
+	default:
+		t.Errorf("expected first timer to fire")
+	}
+
+	select {
+	case <-second:
+		t.Errorf("expected second timer not to fire yet")
+	default:
+	}
+
+	clock.Advance(time.Second)
+
+	select {
+	case <-second:
+	default:
+		t.Errorf("expected second timer to fire")
+	}
+
+	if now := clock.Now(); !now.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("expected clock at 2s, got %v", now)
+	}
+}
+
+func TestClockStopAndBlockUntilTimers(t *testing.T) {
+	t.Parallel()
+
+	clock := clocktest.NewClock(time.Unix(0, 0))
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		<-clock.After(time.Minute)
+	}()
+
+	clock.BlockUntilTimers(1)
+	clock.Advance(time.Minute)
+	<-done
+
+	timer := clock.NewTimer(time.Second)
+	if !timer.Stop() {
+		t.Errorf("expected Stop to report a pending timer")
+	}
+
+	if timer.Stop() {
+		t.Errorf("expected second Stop to report a stopped timer")
+	}
+
+	if len(clock.Timers()) != 0 {
+		t.Errorf("expected no pending timers, got %v", clock.Timers())
+	}
+}
@@ -1,6 +1,9 @@
 package gendure
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 const (
 	defaultFailureThreshold = 1
@@ -8,10 +11,25 @@ const (
 	defaultRecoveryTimeout  = defaultTimeToRecovery * time.Second
 )
 
+const (
+	defaultSlidingWindowSize      = 100
+	defaultMinimumCalls           = 10
+	defaultPermittedHalfOpenCalls = 1
+	percent                       = 100
+)
+
 const (
 	defaultNumberToDelay = 100
 	defaultInitialDelay  = defaultNumberToDelay * time.Millisecond
 	defaultMaxRetries    = 3
 	defaultMultiplier    = 2
 	defaultRandomInt     = 1
+
+	defaultPolynomialExponent = 2
+	decorrelatedJitterGrowth  = 3
+
+	defaultRetryBudgetWindow = 10 * time.Second
+
+	// maxDuration is the largest representable delay; computed delays saturate at it.
+	maxDuration = time.Duration(math.MaxInt64)
 )
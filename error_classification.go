@@ -0,0 +1,91 @@
+package gendure
+
+import "errors"
+
+// errorClass tells how the circuit breaker accounts an error returned by an operation.
+type errorClass int
+
+const (
+	// errorClassFailure counts the error as a failed call.
+	errorClassFailure errorClass = iota
+
+	// errorClassSuccess counts the error as a successful call.
+	errorClassSuccess
+
+	// errorClassIgnored records neither a success nor a failure.
+	errorClassIgnored
+)
+
+// errorClassifier decides which operation errors trip the circuit breaker.
+// The zero value counts every error as a failure.
+type errorClassifier struct {
+	// ignored lists errors, matched with errors.Is, that are ignored entirely.
+	ignored []error
+
+	// ignoreIf lists predicates reporting errors that are ignored entirely.
+	ignoreIf []func(err error) bool
+
+	// successes lists errors, matched with errors.Is, that count as successful calls.
+	successes []error
+
+	// isFailure reports whether an error counts as a failure. Nil counts every error.
+	isFailure func(err error) bool
+}
+
+// classify returns the class of err. Ignore rules take precedence over success rules,
+// which take precedence over the failure predicate.
+func (c errorClassifier) classify(err error) errorClass {
+	if matchesAny(err, c.ignored) {
+		return errorClassIgnored
+	}
+
+	for _, ignore := range c.ignoreIf {
+		if ignore(err) {
+			return errorClassIgnored
+		}
+	}
+
+	if matchesAny(err, c.successes) {
+		return errorClassSuccess
+	}
+
+	if c.isFailure != nil && !c.isFailure(err) {
+		return errorClassSuccess
+	}
+
+	return errorClassFailure
+}
+
+// matchesAny reports whether err matches any of the targets with errors.Is.
+func matchesAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsErrorType returns a predicate reporting whether an error, or any error it wraps,
+// is of type E. Matching uses errors.As, so it can be combined with classification
+// options to select errors by type rather than by value.
+//
+// Type Parameters:
+//   - E: The error type to match, usually a pointer to a custom error struct
+//
+// Returns:
+//   - func(error) bool: Predicate matching errors of type E
+//
+// Example:
+//
+//	cb := NewCircuitBreaker[*http.Response](5, 30*time.Second, nil,
+//	    WithIgnoreErrorIf(IsErrorType[*ValidationError]()),
+//	)
+func IsErrorType[E error]() func(err error) bool {
+	return func(err error) bool {
+		var target E
+
+		return errors.As(err, &target)
+	}
+}
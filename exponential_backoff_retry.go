@@ -2,7 +2,7 @@ package gendure
 
 import (
 	"context"
-	"crypto/rand"
+	"errors"
 	"time"
 
 	"github.com/marincor/gendure/glogger"
@@ -19,6 +19,61 @@ import (
 //   - error: Error if the operation fails, nil on success
 type CallbackFunc[T any] func() (T, error)
 
+// CallbackContextFunc represents a context-aware function that returns a value of type T and an error.
+// Each attempt receives the context passed to Execute, so in-flight work stops on cancellation.
+// With WithAttemptTimeout, it receives a context derived from it that is also cancelled when the
+// per-attempt timeout elapses or the attempt returns.
+//
+// Type Parameters:
+//   - T: The return type of the callback function
+//
+// Parameters:
+//   - ctx: The attempt-scoped context
+//
+// Returns:
+//   - T: The result of the operation
+//   - error: Error if the operation fails, nil on success
+type CallbackContextFunc[T any] func(ctx context.Context) (T, error)
+
+// Attempt describes the attempt a CallbackAttemptFunc is called for.
+type Attempt struct {
+	// Number is the number of the attempt, starting at 1.
+	Number int
+
+	// PreviousErr is the error of the previous attempt, ErrRetryableResult if its result was
+	// retried, or nil on the first attempt.
+	PreviousErr error
+
+	// Elapsed is the time since the first attempt started.
+	Elapsed time.Duration
+
+	// RemainingAttempts is the number of attempts left after this one.
+	RemainingAttempts int
+
+	// RemainingTime is the time left before the elapsed time budget (see WithMaxElapsedTime)
+	// or the context deadline, whichever comes first. Only meaningful if TimeBounded is true.
+	RemainingTime time.Duration
+
+	// TimeBounded reports whether an elapsed time budget or a context deadline applies.
+	TimeBounded bool
+}
+
+// CallbackAttemptFunc represents a context-aware function that also receives the attempt it is
+// called for, so it can switch to a replica on later attempts, reuse an idempotency key or log
+// the attempt number.
+//
+// Type Parameters:
+//   - T: The return type of the callback function
+//
+// Parameters:
+//   - ctx: The attempt-scoped context
+//   - attempt: The attempt number, previous error, elapsed time and remaining budget
+//
+// Returns:
+//   - T: The result of the operation
+//   - error: Error if the operation fails, nil on success
+type CallbackAttemptFunc[T any] func(ctx context.Context, attempt Attempt) (T, error)
+
 // ExponentialBackoffRetry implements the Exponential Backoff retry pattern with jitter.
 // It retries failed operations with exponentially increasing delays between attempts,
 // adding random jitter to prevent thundering herd problems.
@@ -30,26 +85,63 @@ type CallbackFunc[T any] func() (T, error)
 //
 //	totalDelay = initialDelay * (multiplier ^ attempt) + randomJitter
 //
-// Where randomJitter is a random duration between 0 and randomInt seconds.
+// Where randomJitter is a random duration between 0 and randomInt-1 seconds.
+// Another delay curve can be selected with WithBackoff, and another jitter with WithJitter.
 type ExponentialBackoffRetry[T any] struct {
 	// callback is the function to be executed and retried on failure.
-	callback CallbackFunc[T]
+	callback CallbackAttemptFunc[T]
 
-	// initialDelay is the base delay duration for the first retry attempt.
-	// Subsequent delays are calculated by multiplying this value exponentially.
-	initialDelay time.Duration
+	// backoff computes the delay between attempts, before jitter.
+	// Defaults to an exponential backoff from the initial delay and multiplier.
+	backoff Backoff
 
 	// maxRetries is the maximum number of retry attempts before giving up.
 	// The total number of executions will be maxRetries (including the initial attempt).
 	maxRetries int
 
-	// multiplier is the factor by which the delay increases with each attempt.
-	// Typically set to 2 for exponential growth (2^attempt).
-	multiplier int
+	// jitter randomizes the backoff delay. Defaults to adding between 0 and randomInt-1 seconds.
+	jitter Jitter
+
+	// random is the random source used by jitter.
+	random RandomSource
+
+	// attemptTimeout bounds the duration of each attempt. Zero means no per-attempt limit.
+	attemptTimeout time.Duration
+
+	// recoverPanics converts callback panics into *PanicError failed attempts.
+	recoverPanics bool
 
-	// randomInt defines the upper bound (in seconds) for random jitter.
-	// A random value between 0 and randomInt-1 seconds is added to each delay.
-	randomInt int
+	// repanic re-panics with the *PanicError when giving up after a panicking last attempt.
+	repanic bool
+
+	// clock provides the timers waited on between attempts.
+	clock Clock
+
+	// retryIf reports whether a failed attempt may be retried. Nil retries every error.
+	retryIf func(err error) bool
+
+	// retryOnResult reports whether a successful attempt's result must be retried.
+	// Nil accepts every result.
+	retryOnResult func(result T) bool
+
+	// maxDelay caps the backoff delay before jitter, and the jittered delay. Zero means no cap.
+	maxDelay time.Duration
+
+	// maxElapsedTime bounds the total retry time: the retry stops instead of sleeping past it.
+	// Zero means no budget other than the context.
+	maxElapsedTime time.Duration
+
+	// hooks are called before each sleep, on give up and on success.
+	hooks retryHooks
+
+	// retryAfterMode tells how delay hints from errors implementing RetryAfterHint are applied.
+	retryAfterMode RetryAfterMode
+
+	// maxRetryAfter caps delay hints. Zero means no cap.
+	maxRetryAfter time.Duration
+
+	// budget is the shared retry budget consulted before each retry. Nil means no budget.
+	budget *RetryBudget
 
 	// glogger is the optional logger instance for debugging and monitoring.
 	// If nil, logging is disabled.
@@ -79,6 +171,8 @@ type ExponentialBackoffRetry[T any] struct {
 //     Helps distribute retry attempts and prevent thundering herd.
 //     Common values: 1-5 seconds.
 //   - glogger: Optional logger for debugging. Pass nil to disable logging.
+//   - opts: Optional settings. Invalid options are skipped and their defaults kept;
+//     use NewExponentialBackoffRetryWithOptions to get a descriptive error instead.
 //
 // Returns:
 //   - ExponentialBackoffRetry[T]: A configured retry instance ready for use
@@ -101,37 +195,229 @@ func NewExponentialBackoffRetry[T any](
 	initialDelay time.Duration,
 	maxRetries, multiplier, randomInt int,
 	glogger glogger.GLogger,
+	opts ...RetryOption,
 ) ExponentialBackoffRetry[T] {
 	if callback == nil {
 		panic("callback cannot be nil")
 	}
 
-	if initialDelay <= 0 {
-		initialDelay = defaultInitialDelay
+	cfg := defaultRetryConfig()
+	cfg.logger = glogger
+
+	if initialDelay > 0 {
+		cfg.initialDelay = initialDelay
 	}
-	if maxRetries <= 0 {
-		maxRetries = defaultMaxRetries
+	if maxRetries > 0 {
+		cfg.maxRetries = maxRetries
 	}
-	if multiplier <= 0 {
-		multiplier = defaultMultiplier
+	if multiplier > 0 {
+		cfg.multiplier = multiplier
 	}
-	if randomInt <= 0 {
-		randomInt = defaultRandomInt
+	if randomInt > 0 {
+		cfg.randomInt = randomInt
 	}
 
+	for _, opt := range opts {
+		if opt != nil {
+			_ = opt.applyRetry(&cfg)
+		}
+	}
+
+	return newExponentialBackoffRetry(func(context.Context, Attempt) (T, error) {
+		return callback()
+	}, cfg)
+}
+
+// NewExponentialBackoffRetryWithOptions creates and initializes a new exponential backoff retry
+// instance configured through options. Unlike NewExponentialBackoffRetry, invalid settings are
+// reported instead of being replaced by defaults, and a nil callback is an error rather than a panic.
+//
+// Type Parameters:
+//   - T: The return type of the operation being retried
+//
+// Parameters:
+//   - callback: The function to execute and retry on failure. Cannot be nil.
+//   - opts: Settings such as WithInitialDelay, WithMaxRetries and WithLogger.
+//     Settings not provided use the same defaults as NewExponentialBackoffRetry.
+//
+// Returns:
+//   - ExponentialBackoffRetry[T]: A configured retry instance ready for use
+//   - error: Every invalid setting joined together, each wrapping ErrInvalidOption
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(
+//	    func() (string, error) { return httpClient.Get(url) },
+//	    WithInitialDelay(100*time.Millisecond),
+//	    WithMaxRetries(5),
+//	    WithLogger(myLogger),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+func NewExponentialBackoffRetryWithOptions[T any](
+	callback CallbackFunc[T],
+	opts ...RetryOption,
+) (ExponentialBackoffRetry[T], error) {
+	if callback == nil {
+		return newExponentialBackoffRetryWithOptions[T](nil, opts)
+	}
+
+	return newExponentialBackoffRetryWithOptions(func(context.Context, Attempt) (T, error) {
+		return callback()
+	}, opts)
+}
+
+// NewExponentialBackoffRetryContext creates and initializes a new exponential backoff retry
+// instance around a context-aware callback. Options are validated as in
+// NewExponentialBackoffRetryWithOptions.
+//
+// Every attempt receives the context passed to Execute, so results tied to it, such as a streamed
+// response body, stay usable after Execute returns. With WithAttemptTimeout, every attempt receives
+// an attempt-scoped context instead, cancelled when the timeout elapses or the attempt returns:
+// results tied to it must then be fully consumed inside the callback.
+//
+// Type Parameters:
+//   - T: The return type of the operation being retried
+//
+// Parameters:
+//   - callback: The context-aware function to execute and retry on failure. Cannot be nil.
+//   - opts: Settings such as WithInitialDelay, WithMaxRetries and WithAttemptTimeout.
+//
+// Returns:
+//   - ExponentialBackoffRetry[T]: A configured retry instance ready for use
+//   - error: Every invalid setting joined together, each wrapping ErrInvalidOption
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryContext(
+//	    func(ctx context.Context) ([]byte, error) {
+//	        req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	        resp, err := http.DefaultClient.Do(req)
+//	        if err != nil {
+//	            return nil, err
+//	        }
+//	        defer resp.Body.Close()
+//	        return io.ReadAll(resp.Body) // read before the attempt context is cancelled
+//	    },
+//	    WithMaxRetries(5),
+//	    WithAttemptTimeout(2*time.Second),
+//	)
+func NewExponentialBackoffRetryContext[T any](
+	callback CallbackContextFunc[T],
+	opts ...RetryOption,
+) (ExponentialBackoffRetry[T], error) {
+	if callback == nil {
+		return newExponentialBackoffRetryWithOptions[T](nil, opts)
+	}
+
+	return newExponentialBackoffRetryWithOptions(withoutAttempt(callback), opts)
+}
+
+// NewExponentialBackoffRetryAttempt creates and initializes a new exponential backoff retry
+// instance around a callback receiving the attempt it is called for. Options are validated as in
+// NewExponentialBackoffRetryWithOptions, and the context is passed as in
+// NewExponentialBackoffRetryContext.
+//
+// Type Parameters:
+//   - T: The return type of the operation being retried
+//
+// Parameters:
+//   - callback: The function to execute and retry on failure. Cannot be nil.
+//   - opts: Settings such as WithInitialDelay, WithMaxRetries and WithMaxElapsedTime.
+//
+// Returns:
+//   - ExponentialBackoffRetry[T]: A configured retry instance ready for use
+//   - error: Every invalid setting joined together, each wrapping ErrInvalidOption
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryAttempt(
+//	    func(ctx context.Context, attempt Attempt) (*Order, error) {
+//	        host := primary
+//	        if attempt.Number > 1 {
+//	            host = replica
+//	        }
+//	        return fetchOrder(ctx, host, orderID)
+//	    },
+//	    WithMaxRetries(3),
+//	)
+func NewExponentialBackoffRetryAttempt[T any](
+	callback CallbackAttemptFunc[T],
+	opts ...RetryOption,
+) (ExponentialBackoffRetry[T], error) {
+	return newExponentialBackoffRetryWithOptions(callback, opts)
+}
+
+// withoutAttempt adapts a context-aware callback ignoring the attempt information.
+func withoutAttempt[T any](callback func(ctx context.Context) (T, error)) CallbackAttemptFunc[T] {
+	return func(ctx context.Context, _ Attempt) (T, error) {
+		return callback(ctx)
+	}
+}
+
+// newExponentialBackoffRetryWithOptions validates the callback and options shared by the
+// option-based constructors and builds the retry instance.
+func newExponentialBackoffRetryWithOptions[T any](
+	callback CallbackAttemptFunc[T],
+	opts []RetryOption,
+) (ExponentialBackoffRetry[T], error) {
+	var errs []error
+
+	if callback == nil {
+		errs = append(errs, invalidOption("callback cannot be nil"))
+	}
+
+	cfg, err := newRetryConfig(opts)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, ok := cfg.retryOnResult.(func(result T) bool); cfg.retryOnResult != nil && !ok {
+		var zero T
+
+		errs = append(errs, invalidOption("result predicate %T does not accept the retry result type %T",
+			cfg.retryOnResult, zero))
+	}
+
+	if len(errs) > 0 {
+		return ExponentialBackoffRetry[T]{}, errors.Join(errs...)
+	}
+
+	return newExponentialBackoffRetry(callback, cfg), nil
+}
+
+// newExponentialBackoffRetry builds a retry instance from a complete configuration.
+func newExponentialBackoffRetry[T any](callback CallbackAttemptFunc[T], cfg retryConfig) ExponentialBackoffRetry[T] {
+	// A predicate for another result type was reported by the option-based constructors
+	retryOnResult, _ := cfg.retryOnResult.(func(result T) bool)
+
 	return ExponentialBackoffRetry[T]{
-		callback:     callback,
-		initialDelay: initialDelay,
-		maxRetries:   maxRetries,
-		multiplier:   multiplier,
-		randomInt:    randomInt,
-		glogger:      glogger,
+		callback:       callback,
+		backoff:        cfg.newBackoff(),
+		maxRetries:     cfg.maxRetries,
+		jitter:         cfg.newJitter(),
+		random:         cfg.random,
+		attemptTimeout: cfg.attemptTimeout,
+		recoverPanics:  cfg.recoverPanics,
+		repanic:        cfg.repanic,
+		clock:          cfg.clock,
+		retryIf:        cfg.retryIf,
+		retryOnResult:  retryOnResult,
+		maxDelay:       cfg.maxDelay,
+		maxElapsedTime: cfg.maxElapsedTime,
+		hooks:          cfg.hooks,
+		retryAfterMode: cfg.retryAfterMode,
+		maxRetryAfter:  cfg.maxRetryAfter,
+		budget:         cfg.budget,
+		glogger:        cfg.logger,
 	}
 }
 
 // Execute runs the callback function with exponential backoff retry logic and context cancellation support.
 // The operation is retried up to maxRetries times with exponentially increasing delays.
-// Respects context cancellation both before callback execution and during delays.
+// Respects context cancellation before callback execution and during delays, and passes the
+// context to context-aware callbacks (see NewExponentialBackoffRetryContext).
 //
 // Execution flow:
 //  1. Checks if context is cancelled before each attempt
@@ -141,8 +427,8 @@ func NewExponentialBackoffRetry[T any](
 //  5. During the delay, monitors context cancellation for early termination
 //  6. Repeats until success, maxRetries exhausted, or context cancelled
 //
-// The delay calculation uses bit shifting for efficient exponential growth:
-// delay = initialDelay * (multiplier^attempt), where multiplier<<attempt equals 2^attempt when multiplier=2
+// The delay before the retry following attempt n (zero-based) is initialDelay * multiplier^n,
+// unless another curve was selected with WithBackoff.
 //
 // Parameters:
 //   - ctx: Context for cancellation control. If cancelled at any point (before execution
@@ -150,7 +436,10 @@ func NewExponentialBackoffRetry[T any](
 //
 // Returns:
 //   - T: The result from the callback if any attempt succeeds, or zero value if context cancelled
-//   - error: nil if successful, ctx.Err() if context cancelled, or the last callback error if retries exhausted
+//   - error: nil if successful, ctx.Err() if context cancelled, the callback error as is if it is
+//     not retryable (see Permanent and WithRetryIf), or a *RetryError holding every attempt error
+//     if retries are exhausted, the elapsed time budget would be exceeded, the next attempt
+//     could not start before the context deadline, or the retry budget is spent
 //
 // Thread-safety:
 //   - Safe to call concurrently from multiple goroutines
@@ -170,35 +459,192 @@ func NewExponentialBackoffRetry[T any](
 //	    }
 //	}
 func (ebr ExponentialBackoffRetry[T]) Execute(ctx context.Context) (T, error) {
+	return ebr.run(ctx, ebr.callback)
+}
+
+// Do runs the given operation with the same exponential backoff retry logic as Execute,
+// implementing the Policy interface. The callback bound at construction is not used, so a
+// single retry value can protect several operations returning type T.
+// Each attempt receives ctx, or an attempt-scoped context derived from it with WithAttemptTimeout,
+// so it can abort in-flight work on cancellation or per-attempt timeout.
+//
+// Parameters:
+//   - ctx: Context for cancellation control, parent of every attempt context
+//   - operation: The function to execute and retry on failure
+//
+// Returns:
+//   - T: The result from the operation if any attempt succeeds, or zero value otherwise
+//   - error: Same as Execute
+//
+// Example:
+//
+//	result, err := retry.Do(ctx, func(ctx context.Context) (string, error) {
+//	    return fetch(ctx, url)
+//	})
+func (ebr ExponentialBackoffRetry[T]) Do(
+	ctx context.Context,
+	operation func(ctx context.Context) (T, error),
+) (T, error) {
+	return ebr.run(ctx, withoutAttempt(operation))
+}
+
+// DoAttempt runs the given operation like Do, passing it the attempt it is called for.
+//
+// Parameters:
+//   - ctx: Context for cancellation control, parent of every attempt context
+//   - operation: The function to execute and retry on failure
+//
+// Returns:
+//   - T: The result from the operation if any attempt succeeds, or zero value otherwise
+//   - error: Same as Execute
+//
+// Example:
+//
+//	result, err := retry.DoAttempt(ctx, func(ctx context.Context, attempt Attempt) (string, error) {
+//	    log.Printf("attempt %d, %d left", attempt.Number, attempt.RemainingAttempts)
+//	    return fetch(ctx, url)
+//	})
+func (ebr ExponentialBackoffRetry[T]) DoAttempt(
+	ctx context.Context,
+	operation CallbackAttemptFunc[T],
+) (T, error) {
+	return ebr.run(ctx, operation)
+}
+
+// run executes the retry loop around callback. Shared by Execute and Do.
+//
+// Parameters:
+//   - ctx: Context for cancellation control, parent of every attempt context
+//   - callback: The function to execute and retry on failure
+//
+// Returns:
+//   - T: The result from the callback if any attempt succeeds, or zero value otherwise
+//   - error: nil if successful, ctx.Err() if context cancelled, the callback error as is if it is
+//     not retryable (see Permanent and WithRetryIf), or a *RetryError holding every attempt error
+//     and matching ErrRetriesExhausted if retries are exhausted, ErrMaxElapsedTime if the next
+//     delay would exceed the budget, or ErrDeadlineWouldExceed if the next attempt could not
+//     start before the context deadline. If every attempt returned a result to retry
+//     (see WithRetryOnResult), the last result is returned and the error matches ErrRetryableResult.
+//
+// Panics:
+//   - With the *PanicError of the last attempt if it panicked and WithPanicRecovery(true) is set
+func (ebr ExponentialBackoffRetry[T]) run(
+	ctx context.Context,
+	callback CallbackAttemptFunc[T],
+) (T, error) {
 	var attempt int
 
+	// previous is the delay waited before the current attempt, for decorrelated jitter
+	var previous time.Duration
+
+	// failures records every failed attempt for the *RetryError
+	var failures []AttemptFailure
+
+	start := ebr.clock.Now()
+
 	for {
+		var zero T
+
 		// Check if context is cancelled before attempting operation
 		select {
 		case <-ctx.Done():
-			var zero T
-
-			return zero, ctx.Err()
+			return ebr.giveUp(ctx, attempt, start, failures, zero, ctx.Err())
 		default:
 		}
 
-		result, err := ebr.callback()
+		startedAt := ebr.clock.Now()
+		result, err := ebr.attempt(ctx, callback, ebr.describeAttempt(ctx, attempt, start, startedAt, failures))
+
 		if err == nil {
-			return result, nil
+			if ebr.retryOnResult == nil || !ebr.retryOnResult(result) {
+				if ebr.budget != nil {
+					ebr.budget.recordSuccess(ebr.clock.Now())
+				}
+
+				callHooks(ctx, ebr.hooks.onSuccess, RetryEvent{
+					Attempt: attempt + 1,
+					Elapsed: ebr.clock.Now().Sub(start),
+				})
+
+				return result, nil
+			}
+
+			// The result asks for a retry; it is kept if the retry gives up
+			err = ErrRetryableResult
+		} else {
+			result = zero
+		}
+
+		failures = append(failures, AttemptFailure{
+			Attempt:   attempt + 1,
+			Err:       err,
+			StartedAt: startedAt,
+			Duration:  ebr.clock.Now().Sub(startedAt),
+		})
+
+		// Stop on permanent or non-retryable errors
+		if !ebr.retryable(err) {
+			return ebr.giveUp(ctx, attempt+1, start, failures, result, unwrapPermanent(err))
 		}
 
 		// Check if we've exhausted all retry attempts
 		if attempt >= ebr.maxRetries-1 {
-			var zero T
+			return ebr.giveUp(ctx, attempt+1, start, failures, result,
+				&RetryError{Reason: ErrRetriesExhausted, Failures: failures})
+		}
+
+		// Cap the backoff before jittering it, so capped delays stay spread out
+		delay := ebr.backoff.Delay(attempt)
+		if ebr.maxDelay > 0 {
+			delay = min(delay, ebr.maxDelay)
+		}
+
+		// The cap still bounds jitter strategies that add to the delay
+		totalDelay := ebr.jitter.Apply(delay, previous, ebr.random)
+		if ebr.maxDelay > 0 {
+			totalDelay = min(totalDelay, ebr.maxDelay)
+		}
+
+		jitter := totalDelay - delay
+
+		// Decorrelated jitter grows from the jittered backoff delay, not from server hints
+		previous = totalDelay
+
+		// Honor the delay requested by the server, if any; the jitter no longer applies then
+		if hinted := ebr.applyRetryAfter(err, totalDelay); hinted != totalDelay {
+			totalDelay = hinted
+			jitter = 0
+		}
+
+		elapsed := ebr.clock.Now().Sub(start)
+
+		// Stop before sleeping past the elapsed time budget
+		if ebr.maxElapsedTime > 0 && totalDelay > ebr.maxElapsedTime-elapsed {
+			return ebr.giveUp(ctx, attempt+1, start, failures, result,
+				&RetryError{Reason: ErrMaxElapsedTime, Failures: failures})
+		}
 
-			return zero, err
+		// Stop instead of sleeping in vain when the next attempt could not start before the deadline
+		if deadline, ok := ctx.Deadline(); ok && totalDelay >= time.Until(deadline) {
+			return ebr.giveUp(ctx, attempt+1, start, failures, result,
+				&RetryError{Reason: ErrDeadlineWouldExceed, Failures: failures})
 		}
 
-		delay := ebr.initialDelay * time.Duration(ebr.multiplier<<attempt) // 2^attempt
+		// Stop when retries sharing the budget already spent it
+		if ebr.budget != nil && !ebr.budget.tryRetry(ebr.clock.Now()) {
+			return ebr.giveUp(ctx, attempt+1, start, failures, result,
+				&RetryError{Reason: ErrRetryBudgetExhausted, Failures: failures})
+		}
 
-		jitter := ebr.GenerateJitter(ebr.randomInt)
+		failures[len(failures)-1].Delay = totalDelay
 
-		totalDelay := delay + jitter
+		callHooks(ctx, ebr.hooks.onRetry, RetryEvent{
+			Attempt: attempt + 1,
+			Err:     err,
+			Delay:   totalDelay,
+			Jitter:  jitter,
+			Elapsed: elapsed,
+		})
 
 		if ebr.glogger != nil {
 			ebr.glogger.Debug(
@@ -212,15 +658,14 @@ func (ebr ExponentialBackoffRetry[T]) Execute(ctx context.Context) (T, error) {
 		}
 
 		// Wait for delay with context cancellation support
-		timer := time.NewTimer(totalDelay)
-		defer timer.Stop()
+		timer := ebr.clock.NewTimer(totalDelay)
 
 		select {
 		case <-ctx.Done():
-			var zero T
+			timer.Stop()
 
-			return zero, ctx.Err()
-		case <-timer.C:
+			return ebr.giveUp(ctx, attempt+1, start, failures, zero, ctx.Err())
+		case <-timer.C():
 			// Delay completed, proceed to next attempt
 		}
 
@@ -228,39 +673,218 @@ func (ebr ExponentialBackoffRetry[T]) Execute(ctx context.Context) (T, error) {
 	}
 }
 
+// retryable reports whether a failed attempt may be retried: the error is not permanent
+// and, if a predicate was set with WithRetryIf, the predicate accepts it.
+// Results retried because of WithRetryOnResult are always retryable.
+//
+// Parameters:
+//   - err: The error returned by the attempt
+//
+// Returns:
+//   - bool: true if the attempt may be retried
+func (ebr ExponentialBackoffRetry[T]) retryable(err error) bool {
+	if errors.Is(err, ErrRetryableResult) {
+		return true
+	}
+
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	return ebr.retryIf == nil || ebr.retryIf(err)
+}
+
+// giveUp ends the retry with err after calling the give-up hooks, re-panicking if the
+// last attempt panicked and WithPanicRecovery(true) is set.
+//
+// Parameters:
+//   - ctx: Context passed to the hooks
+//   - attempts: Number of attempts made
+//   - start: Time the first attempt started
+//   - failures: Every failed attempt, in order
+//   - result: The result to return: the last result if it was retried because of
+//     WithRetryOnResult, zero value otherwise
+//   - err: The error returned by the retry
+//
+// Returns:
+//   - T: result
+//   - error: err
+func (ebr ExponentialBackoffRetry[T]) giveUp(
+	ctx context.Context,
+	attempts int,
+	start time.Time,
+	failures []AttemptFailure,
+	result T,
+	err error,
+) (T, error) {
+	callHooks(ctx, ebr.hooks.onGiveUp, RetryEvent{
+		Attempt: attempts,
+		Err:     err,
+		Elapsed: ebr.clock.Now().Sub(start),
+	})
+
+	if ebr.repanic && len(failures) > 0 {
+		var panicErr *PanicError
+		if errors.As(failures[len(failures)-1].Err, &panicErr) {
+			panic(panicErr)
+		}
+	}
+
+	return result, err
+}
+
+// addDelays returns a + b for non-negative delays, saturating instead of overflowing time.Duration.
+func addDelays(a, b time.Duration) time.Duration {
+	if b > maxDuration-a {
+		return maxDuration
+	}
+
+	return a + b
+}
+
+// applyRetryAfter adjusts the computed delay to the hint of an error implementing RetryAfterHint,
+// according to the RetryAfterMode and capped by WithMaxRetryAfter.
+//
+// Parameters:
+//   - err: The error of the failed attempt
+//   - delay: The delay computed by the backoff and jitter
+//
+// Returns:
+//   - time.Duration: The delay to wait before the next attempt
+func (ebr ExponentialBackoffRetry[T]) applyRetryAfter(err error, delay time.Duration) time.Duration {
+	if ebr.retryAfterMode == RetryAfterIgnore {
+		return delay
+	}
+
+	var hint RetryAfterHint
+	if !errors.As(err, &hint) {
+		return delay
+	}
+
+	retryAfter := hint.RetryAfter()
+	if retryAfter <= 0 {
+		return delay
+	}
+
+	if ebr.maxRetryAfter > 0 {
+		retryAfter = min(retryAfter, ebr.maxRetryAfter)
+	}
+
+	if ebr.retryAfterMode == RetryAfterOverride {
+		return retryAfter
+	}
+
+	return max(delay, retryAfter)
+}
+
+// describeAttempt returns the Attempt passed to the callback.
+//
+// Parameters:
+//   - ctx: Context passed to the retry, whose deadline bounds the remaining time
+//   - attempt: Zero-based number of the attempt
+//   - start: Time the first attempt started
+//   - now: Time the attempt starts
+//   - failures: Every failed attempt so far
+//
+// Returns:
+//   - Attempt: The attempt information
+func (ebr ExponentialBackoffRetry[T]) describeAttempt(
+	ctx context.Context,
+	attempt int,
+	start, now time.Time,
+	failures []AttemptFailure,
+) Attempt {
+	info := Attempt{
+		Number:            attempt + 1,
+		Elapsed:           now.Sub(start),
+		RemainingAttempts: max(ebr.maxRetries-attempt-1, 0),
+	}
+
+	if len(failures) > 0 {
+		info.PreviousErr = failures[len(failures)-1].Err
+	}
+
+	if ebr.maxElapsedTime > 0 {
+		info.RemainingTime = ebr.maxElapsedTime - info.Elapsed
+		info.TimeBounded = true
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		// Context deadlines expire in real time, whatever the clock
+		untilDeadline := time.Until(deadline)
+		if !info.TimeBounded || untilDeadline < info.RemainingTime {
+			info.RemainingTime = untilDeadline
+		}
+
+		info.TimeBounded = true
+	}
+
+	return info
+}
+
+// attempt runs a single attempt of callback. With a per-attempt timeout, the callback receives an
+// attempt-scoped context cancelled when the timeout elapses or the attempt returns; otherwise it
+// receives ctx itself, so results tied to it stay usable after the attempt.
+// With panic recovery enabled, a panic is returned as a *PanicError so it is retried.
+//
+// Parameters:
+//   - ctx: Parent context of the attempt
+//   - callback: The function to execute
+//   - info: The attempt passed to the callback
+//
+// Returns:
+//   - T: The result from the callback
+//   - error: The error returned by the callback, or a *PanicError if it panicked
+func (ebr ExponentialBackoffRetry[T]) attempt(
+	ctx context.Context,
+	callback CallbackAttemptFunc[T],
+	info Attempt,
+) (result T, err error) {
+	if ebr.recoverPanics {
+		defer func() {
+			if value := recover(); value != nil {
+				var zero T
+
+				result, err = zero, newPanicError(value)
+			}
+		}()
+	}
+
+	if ebr.attemptTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, ebr.attemptTimeout)
+		defer cancel()
+	}
+
+	return callback(ctx, info)
+}
+
 // GenerateJitter generates a random duration to add to retry delays.
 // This prevents the "thundering herd" problem where multiple clients
 // retry simultaneously, overwhelming the recovering service.
 //
-// The jitter is calculated using cryptographically secure random bytes
+// The jitter is calculated using cryptographically secure random numbers
 // to ensure good distribution of retry attempts across time.
+// Sub-second and other jitter strategies are available through WithJitter.
 //
 // Parameters:
 //   - randomInt: Maximum jitter value in seconds. The actual jitter will be
 //     between 0 and (randomInt-1) seconds.
 //
 // Returns:
-//   - time.Duration: Random jitter duration between 0 and (randomInt-1) seconds
+//   - time.Duration: Random jitter duration between 0 and (randomInt-1) seconds,
+//     or 0 if randomInt <= 1
 //
 // Implementation notes:
 //   - Uses crypto/rand for secure random number generation
-//   - Falls back to randomInt if random generation fails
-//   - Uses modulo operation to constrain the random value to the desired range
+//   - Falls back to 0 if random generation fails
+//   - Draws uniformly from the range, without modulo bias
 //
 // Example:
 //
 //	jitter := ebr.GenerateJitter(5) // Returns 0-4 seconds randomly
 func (ebr ExponentialBackoffRetry[T]) GenerateJitter(randomInt int) time.Duration {
-	necessaryAmountOfBytes := 1
-	randomValue := make([]byte, necessaryAmountOfBytes)
-	randomByte := randomInt
-
-	if _, err := rand.Read(randomValue); err == nil {
-		randomByte = int(randomValue[0])
-	}
-
-	// limit bytes between 0 and randomInt -1 (because of % operator)
-	jitter := time.Duration(randomByte%randomInt) * time.Second
-
-	return jitter
+	return secondsJitter{seconds: randomInt}.Apply(0, 0, cryptoRandomSource{})
 }
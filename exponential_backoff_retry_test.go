@@ -4,10 +4,14 @@ package gendure_test
 import (
 	"context"
 	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/marincor/gendure"
+	"github.com/marincor/gendure/clocktest"
 	"github.com/marincor/gendure/glogger"
 )
 
@@ -192,3 +196,862 @@ func TestGenerateJitterReturnsWithinExpectedRange(t *testing.T) {
 		}
 	}
 }
+
+func TestExponentialBackoffRetryContextPassesParentContext(t *testing.T) {
+	var contexts []context.Context
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryContext(
+		func(ctx context.Context) (string, error) {
+			contexts = append(contexts, ctx)
+			if len(contexts) < 2 {
+				return "", errors.New("temporary error")
+			}
+
+			return success, nil
+		},
+		gendure.WithInitialDelay(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	type key struct{}
+
+	parent := context.WithValue(context.Background(), key{}, "value")
+
+	result, err := exponetionalRetry.Execute(parent)
+	if err != nil {
+		t.Errorf(errorWantSuccessGotError, err)
+	}
+
+	if result != success {
+		t.Errorf(errorWantSuccessGot, result)
+	}
+
+	if len(contexts) != 2 {
+		t.Fatalf("want 2 calls, got %d", len(contexts))
+	}
+
+	for i, ctx := range contexts {
+		if ctx.Value(key{}) != "value" {
+			t.Errorf("attempt %d: want context derived from parent", i)
+		}
+
+		if ctx.Err() != nil {
+			t.Errorf("attempt %d: want context still usable after the attempt, got %v", i, ctx.Err())
+		}
+	}
+}
+
+func TestExponentialBackoffRetryContextResultUsableAfterExecute(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "streamed ")
+		w.(http.Flusher).Flush()
+
+		// The rest of the body is only sent once the retry has returned
+		<-release
+
+		_, _ = io.WriteString(w, "body")
+	}))
+	defer server.Close()
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryContext(
+		func(ctx context.Context) (*http.Response, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			return http.DefaultClient.Do(req)
+		},
+		gendure.WithInitialDelay(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	resp, err := exponetionalRetry.Execute(context.Background())
+	if err != nil {
+		t.Fatalf(errorWantSuccessGotError, err)
+	}
+	defer resp.Body.Close()
+
+	close(release)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "streamed body" {
+		t.Errorf("want 'streamed body', got '%s' and %v", body, err)
+	}
+}
+
+func TestExponentialBackoffRetryContextAttemptTimeout(t *testing.T) {
+	callCount := 0
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryContext(
+		func(ctx context.Context) (string, error) {
+			callCount++
+			if callCount == 1 {
+				<-ctx.Done()
+
+				return "", ctx.Err()
+			}
+
+			return success, nil
+		},
+		gendure.WithInitialDelay(time.Millisecond),
+		gendure.WithAttemptTimeout(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	result, err := exponetionalRetry.Execute(context.Background())
+	if err != nil {
+		t.Errorf(errorWantSuccessGotError, err)
+	}
+
+	if result != success || callCount != 2 {
+		t.Errorf("want success on the second call, got '%s' after %d calls", result, callCount)
+	}
+}
+
+func TestExponentialBackoffRetryContextCancelledMidCall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryContext(
+		func(ctx context.Context) (string, error) {
+			cancel()
+			<-ctx.Done()
+
+			return "", ctx.Err()
+		},
+		gendure.WithInitialDelay(time.Millisecond),
+		gendure.WithMaxRetries(5),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	_, err = exponetionalRetry.Execute(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+}
+
+func TestExponentialBackoffRetryPanicRecovery(t *testing.T) {
+	callCount := 0
+
+	exponetionalRetry := gendure.NewExponentialBackoffRetry(
+		func() (string, error) {
+			callCount++
+			if callCount == 1 {
+				panic("boom")
+			}
+
+			return success, nil
+		},
+		time.Millisecond,
+		3,
+		2,
+		1,
+		nil,
+		gendure.WithPanicRecovery(false),
+	)
+
+	result, err := exponetionalRetry.Execute(context.Background())
+	if err != nil {
+		t.Errorf(errorWantSuccessGotError, err)
+	}
+
+	if result != success || callCount != 2 {
+		t.Errorf("want success on the second call, got '%s' after %d calls", result, callCount)
+	}
+}
+
+func TestExponentialBackoffRetryPanicRecoveryRepanicsWhenGivingUp(t *testing.T) {
+	callCount := 0
+
+	exponetionalRetry := gendure.NewExponentialBackoffRetry(
+		func() (string, error) {
+			callCount++
+
+			panic("boom")
+		},
+		time.Millisecond,
+		2,
+		2,
+		1,
+		nil,
+		gendure.WithPanicRecovery(true),
+	)
+
+	defer func() {
+		panicErr, ok := recover().(*gendure.PanicError)
+		if !ok || panicErr.Value != "boom" {
+			t.Errorf("want re-panic with *PanicError, got %v", panicErr)
+		}
+
+		if callCount != 2 {
+			t.Errorf("want 2 calls before re-panicking, got %d", callCount)
+		}
+	}()
+
+	_, _ = exponetionalRetry.Execute(context.Background())
+}
+
+func TestExponentialBackoffRetryWaitsOnClock(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	callCount := 0
+
+	exponetionalRetry := gendure.NewExponentialBackoffRetry(
+		func() (string, error) {
+			callCount++
+			if callCount < 3 {
+				return "", errors.New("temporary error")
+			}
+
+			return success, nil
+		},
+		time.Second,
+		3,
+		2,
+		1,
+		nil,
+		gendure.WithClock(clock),
+	)
+
+	type outcome struct {
+		result string
+		err    error
+	}
+
+	done := make(chan outcome, 1)
+
+	go func() {
+		result, err := exponetionalRetry.Execute(context.Background())
+		done <- outcome{result, err}
+	}()
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntilTimers(1)
+
+		deadlines := clock.Timers()
+		if wait := deadlines[0].Sub(clock.Now()); wait != delay {
+			t.Errorf("want delay %s, got %s", delay, wait)
+		}
+
+		clock.Advance(delay)
+	}
+
+	got := <-done
+	if got.err != nil {
+		t.Errorf(errorWantSuccessGotError, got.err)
+	}
+
+	if got.result != success || callCount != 3 {
+		t.Errorf("want success on the third call, got '%s' after %d calls", got.result, callCount)
+	}
+}
+
+func TestExponentialBackoffRetryStopsOnPermanentError(t *testing.T) {
+	errInvalid := errors.New("invalid request")
+	callCount := 0
+
+	exponetionalRetry := gendure.NewExponentialBackoffRetry(
+		func() (string, error) {
+			callCount++
+
+			return "", gendure.Permanent(errInvalid)
+		},
+		time.Millisecond,
+		5,
+		2,
+		1,
+		nil,
+	)
+
+	_, err := exponetionalRetry.Execute(context.Background())
+	if err != errInvalid {
+		t.Errorf("want the original error, got %v", err)
+	}
+
+	if callCount != 1 {
+		t.Errorf(errorWant1CallGot, callCount)
+	}
+}
+
+func TestExponentialBackoffRetryIf(t *testing.T) {
+	errInvalid := errors.New("invalid request")
+	callCount := 0
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			callCount++
+			if callCount == 1 {
+				return "", errors.New("temporary error")
+			}
+
+			return "", errInvalid
+		},
+		gendure.WithInitialDelay(time.Millisecond),
+		gendure.WithMaxRetries(5),
+		gendure.WithRetryIf(func(err error) bool {
+			return !errors.Is(err, errInvalid)
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	_, err = exponetionalRetry.Execute(context.Background())
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("want the non-retryable error, got %v", err)
+	}
+
+	if callCount != 2 {
+		t.Errorf("want 2 calls, got %d", callCount)
+	}
+
+	if gendure.Permanent(nil) != nil {
+		t.Errorf("want Permanent(nil) to be nil")
+	}
+}
+
+func TestExponentialBackoffRetryOnResult(t *testing.T) {
+	statuses := []string{"pending", "pending", "done"}
+	callCount := 0
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			status := statuses[callCount]
+			callCount++
+
+			return status, nil
+		},
+		gendure.WithInitialDelay(time.Millisecond),
+		gendure.WithRetryOnResult(func(status string) bool {
+			return status == "pending"
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	result, err := exponetionalRetry.Execute(context.Background())
+	if err != nil || result != "done" || callCount != 3 {
+		t.Errorf("want 'done' after 3 calls, got '%s' and %v after %d calls", result, err, callCount)
+	}
+}
+
+func TestExponentialBackoffRetryOnResultExhausted(t *testing.T) {
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			return "pending", nil
+		},
+		gendure.WithInitialDelay(time.Millisecond),
+		gendure.WithMaxRetries(2),
+		gendure.WithRetryOnResult(func(status string) bool {
+			return status == "pending"
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	result, err := exponetionalRetry.Execute(context.Background())
+	if !errors.Is(err, gendure.ErrRetryableResult) || result != "pending" {
+		t.Errorf("want last result with ErrRetryableResult, got '%s' and %v", result, err)
+	}
+
+	_, err = gendure.NewExponentialBackoffRetryWithOptions(
+		func() (int, error) {
+			return 0, nil
+		},
+		gendure.WithRetryOnResult(func(status string) bool {
+			return status == "pending"
+		}),
+	)
+	if !errors.Is(err, gendure.ErrInvalidOption) {
+		t.Errorf("want ErrInvalidOption for a mismatched result predicate, got %v", err)
+	}
+}
+
+func TestExponentialBackoffRetryMaxDelayCapsOverflowingDelays(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	errTemporary := errors.New("temporary error")
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			return "", errTemporary
+		},
+		gendure.WithInitialDelay(time.Second),
+		gendure.WithMaxRetries(80),
+		gendure.WithMaxDelay(time.Minute),
+		gendure.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	for attempt := 0; attempt < 79; attempt++ {
+		clock.BlockUntilTimers(1)
+
+		wait := clock.Timers()[0].Sub(clock.Now())
+		if wait <= 0 || wait > time.Minute {
+			t.Fatalf("attempt %d: want delay in (0, 1m], got %s", attempt, wait)
+		}
+
+		clock.Advance(wait)
+	}
+
+	if err := <-done; !errors.Is(err, errTemporary) {
+		t.Errorf("want the last callback error, got %v", err)
+	}
+}
+
+func TestExponentialBackoffRetryMaxElapsedTime(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	errTemporary := errors.New("temporary error")
+	callCount := 0
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			callCount++
+
+			return "", errTemporary
+		},
+		gendure.WithInitialDelay(time.Second),
+		gendure.WithMaxRetries(10),
+		gendure.WithMaxElapsedTime(5*time.Second),
+		gendure.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	// The first two delays (1s, 2s) fit the budget, the third (4s) would end at 7s
+	clock.BlockUntilTimers(1)
+	clock.Advance(time.Second)
+	clock.BlockUntilTimers(1)
+	clock.Advance(2 * time.Second)
+
+	err = <-done
+	if !errors.Is(err, gendure.ErrMaxElapsedTime) || !errors.Is(err, errTemporary) {
+		t.Errorf("want ErrMaxElapsedTime wrapping the last error, got %v", err)
+	}
+
+	if callCount != 3 {
+		t.Errorf("want 3 calls, got %d", callCount)
+	}
+}
+
+func TestExponentialBackoffRetryHooks(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	errTemporary := errors.New("temporary error")
+	callCount := 0
+
+	var retries, successes, giveUps []gendure.RetryEvent
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			callCount++
+			clock.Advance(100 * time.Millisecond)
+
+			if callCount < 3 {
+				return "", errTemporary
+			}
+
+			return success, nil
+		},
+		gendure.WithInitialDelay(time.Second),
+		gendure.WithClock(clock),
+		gendure.WithOnRetry(func(ctx context.Context, event gendure.RetryEvent) {
+			retries = append(retries, event)
+		}),
+		gendure.WithOnSuccess(func(ctx context.Context, event gendure.RetryEvent) {
+			successes = append(successes, event)
+		}),
+		gendure.WithOnGiveUp(func(ctx context.Context, event gendure.RetryEvent) {
+			giveUps = append(giveUps, event)
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntilTimers(1)
+		clock.Advance(delay)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf(errorWantSuccessGotError, err)
+	}
+
+	if len(retries) != 2 || len(successes) != 1 || len(giveUps) != 0 {
+		t.Fatalf("want 2 retries, 1 success and no give up, got %d, %d and %d", len(retries), len(successes), len(giveUps))
+	}
+
+	want := []gendure.RetryEvent{
+		{Attempt: 1, Err: errTemporary, Delay: time.Second, Elapsed: 100 * time.Millisecond},
+		{Attempt: 2, Err: errTemporary, Delay: 2 * time.Second, Elapsed: 1200 * time.Millisecond},
+	}
+	for i, event := range retries {
+		if event != want[i] {
+			t.Errorf("retry %d: want %+v, got %+v", i, want[i], event)
+		}
+	}
+
+	if successes[0].Attempt != 3 || successes[0].Elapsed != 3300*time.Millisecond {
+		t.Errorf("want success on attempt 3 after 3.3s, got %+v", successes[0])
+	}
+}
+
+func TestExponentialBackoffRetryOnGiveUp(t *testing.T) {
+	errInvalid := errors.New("invalid request")
+
+	var giveUps []gendure.RetryEvent
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			return "", gendure.Permanent(errInvalid)
+		},
+		gendure.WithOnGiveUp(func(ctx context.Context, event gendure.RetryEvent) {
+			giveUps = append(giveUps, event)
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	_, _ = exponetionalRetry.Execute(context.Background())
+
+	if len(giveUps) != 1 || giveUps[0].Attempt != 1 || giveUps[0].Err != errInvalid {
+		t.Errorf("want a single give up after attempt 1 with the original error, got %+v", giveUps)
+	}
+}
+
+func TestExponentialBackoffRetryErrorKeepsEveryAttempt(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	errs := []error{errors.New("first"), errors.New("second"), errors.New("third")}
+	callCount := 0
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			err := errs[callCount]
+			callCount++
+
+			return "", err
+		},
+		gendure.WithInitialDelay(time.Second),
+		gendure.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntilTimers(1)
+		clock.Advance(delay)
+	}
+
+	err = <-done
+
+	var retryErr *gendure.RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("want *RetryError, got %v", err)
+	}
+
+	if !errors.Is(err, gendure.ErrRetriesExhausted) || retryErr.Last() != errs[2] {
+		t.Errorf("want retries exhausted with the third error last, got %v", err)
+	}
+
+	for i, failure := range retryErr.Failures {
+		if !errors.Is(err, errs[i]) {
+			t.Errorf("want errors.Is to match attempt %d", i+1)
+		}
+
+		if failure.Attempt != i+1 || failure.Err != errs[i] {
+			t.Errorf("want attempt %d with %v, got %+v", i+1, errs[i], failure)
+		}
+	}
+
+	wantStarts := []time.Duration{0, time.Second, 3 * time.Second}
+	wantDelays := []time.Duration{time.Second, 2 * time.Second, 0}
+
+	for i, failure := range retryErr.Failures {
+		if got := failure.StartedAt.Sub(time.Unix(0, 0)); got != wantStarts[i] || failure.Delay != wantDelays[i] {
+			t.Errorf("attempt %d: want start %s and delay %s, got %s and %s",
+				i+1, wantStarts[i], wantDelays[i], got, failure.Delay)
+		}
+	}
+}
+
+func TestExponentialBackoffRetryAttemptInfo(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	errTemporary := errors.New("temporary error")
+
+	var attempts []gendure.Attempt
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryAttempt(
+		func(ctx context.Context, attempt gendure.Attempt) (string, error) {
+			attempts = append(attempts, attempt)
+			if attempt.Number < 3 {
+				return "", errTemporary
+			}
+
+			return success, nil
+		},
+		gendure.WithInitialDelay(time.Second),
+		gendure.WithMaxRetries(4),
+		gendure.WithMaxElapsedTime(time.Minute),
+		gendure.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntilTimers(1)
+		clock.Advance(delay)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf(errorWantSuccessGotError, err)
+	}
+
+	want := []gendure.Attempt{
+		{Number: 1, RemainingAttempts: 3, RemainingTime: time.Minute, TimeBounded: true},
+		{Number: 2, PreviousErr: errTemporary, Elapsed: time.Second, RemainingAttempts: 2, RemainingTime: 59 * time.Second, TimeBounded: true},
+		{Number: 3, PreviousErr: errTemporary, Elapsed: 3 * time.Second, RemainingAttempts: 1, RemainingTime: 57 * time.Second, TimeBounded: true},
+	}
+
+	if len(attempts) != len(want) {
+		t.Fatalf("want %d attempts, got %d", len(want), len(attempts))
+	}
+
+	for i := range want {
+		if attempts[i] != want[i] {
+			t.Errorf("attempt %d: want %+v, got %+v", i+1, want[i], attempts[i])
+		}
+	}
+}
+
+func TestExponentialBackoffRetryHonorsRetryAfter(t *testing.T) {
+	errRateLimited := errors.New("rate limited")
+
+	// Every computed delay is 1s of backoff plus 100ms of jitter
+	jitter := gendure.JitterFunc(func(delay, _ time.Duration, _ gendure.RandomSource) time.Duration {
+		return delay + 100*time.Millisecond
+	})
+
+	tests := []struct {
+		name   string
+		hint   time.Duration
+		opts   []gendure.RetryOption
+		delay  time.Duration
+		jitter time.Duration
+	}{
+		{name: "floor raises the delay", hint: 5 * time.Second, delay: 5 * time.Second},
+		{
+			name:   "floor keeps a longer delay",
+			hint:   100 * time.Millisecond,
+			delay:  1100 * time.Millisecond,
+			jitter: 100 * time.Millisecond,
+		},
+		{
+			name:  "override lowers the delay",
+			hint:  100 * time.Millisecond,
+			opts:  []gendure.RetryOption{gendure.WithRetryAfterMode(gendure.RetryAfterOverride)},
+			delay: 100 * time.Millisecond,
+		},
+		{
+			name:  "cap limits the hint",
+			hint:  time.Hour,
+			opts:  []gendure.RetryOption{gendure.WithMaxRetryAfter(10 * time.Second)},
+			delay: 10 * time.Second,
+		},
+		{
+			name:   "ignore keeps the computed delay",
+			hint:   time.Hour,
+			opts:   []gendure.RetryOption{gendure.WithRetryAfterMode(gendure.RetryAfterIgnore)},
+			delay:  1100 * time.Millisecond,
+			jitter: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := clocktest.NewClock(time.Unix(0, 0))
+			callCount := 0
+			events := make(chan gendure.RetryEvent, 1)
+
+			opts := append([]gendure.RetryOption{
+				gendure.WithInitialDelay(time.Second),
+				gendure.WithMaxRetries(2),
+				gendure.WithJitter(jitter),
+				gendure.WithClock(clock),
+				gendure.WithOnRetry(func(_ context.Context, event gendure.RetryEvent) {
+					events <- event
+				}),
+			}, tt.opts...)
+
+			exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+				func() (string, error) {
+					callCount++
+					if callCount == 1 {
+						return "", gendure.RetryAfter(errRateLimited, tt.hint)
+					}
+
+					return success, nil
+				},
+				opts...,
+			)
+			if err != nil {
+				t.Fatalf(unexpected, err)
+			}
+
+			done := make(chan error, 1)
+
+			go func() {
+				_, err := exponetionalRetry.Execute(context.Background())
+				done <- err
+			}()
+
+			clock.BlockUntilTimers(1)
+
+			if wait := clock.Timers()[0].Sub(clock.Now()); wait != tt.delay {
+				t.Errorf("want delay %s, got %s", tt.delay, wait)
+			}
+
+			if event := <-events; event.Delay != tt.delay || event.Jitter != tt.jitter {
+				t.Errorf("want retry event with delay %s and jitter %s, got %s and %s",
+					tt.delay, tt.jitter, event.Delay, event.Jitter)
+			}
+
+			clock.Advance(tt.delay)
+
+			if err := <-done; err != nil {
+				t.Errorf(errorWantSuccessGotError, err)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoffRetryJitterGrowsWithoutRetryAfter(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	previousDelays := make(chan time.Duration, 2)
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			return "", gendure.RetryAfter(errOperation, time.Hour)
+		},
+		gendure.WithInitialDelay(time.Second),
+		gendure.WithMaxRetries(3),
+		gendure.WithClock(clock),
+		gendure.WithJitter(gendure.JitterFunc(func(delay, previous time.Duration, _ gendure.RandomSource) time.Duration {
+			previousDelays <- previous
+
+			return delay
+		})),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	for range 2 {
+		clock.BlockUntilTimers(1)
+		clock.Advance(time.Hour)
+	}
+
+	<-done
+
+	<-previousDelays
+	if previous := <-previousDelays; previous != time.Second {
+		t.Errorf("want the jitter to grow from the 1s backoff delay, got %s", previous)
+	}
+}
+
+func TestExponentialBackoffRetrySkipsSleepPastDeadline(t *testing.T) {
+	errTemporary := errors.New("temporary error")
+	callCount := 0
+
+	exponetionalRetry := gendure.NewExponentialBackoffRetry(
+		func() (string, error) {
+			callCount++
+
+			return "", errTemporary
+		},
+		5*time.Second,
+		3,
+		2,
+		1,
+		nil,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := exponetionalRetry.Execute(ctx)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("want to return without sleeping, took %s", elapsed)
+	}
+
+	if !errors.Is(err, gendure.ErrDeadlineWouldExceed) || !errors.Is(err, errTemporary) {
+		t.Errorf("want ErrDeadlineWouldExceed with the callback error, got %v", err)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want the error to match context.DeadlineExceeded, got %v", err)
+	}
+
+	if callCount != 1 {
+		t.Errorf(errorWant1CallGot, callCount)
+	}
+}
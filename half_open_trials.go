@@ -0,0 +1,63 @@
+package gendure
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// halfOpenTrial identifies a call admitted as a HalfOpen trial request.
+// The zero value describes a call that is not a trial request.
+type halfOpenTrial struct {
+	// admitted reports whether the call was admitted as a trial request.
+	admitted bool
+
+	// period is the HalfOpen period the trial request was admitted in.
+	period uint32
+}
+
+// trialCounter counts the trial requests of a single HalfOpen period.
+// The period is packed with the count in one atomic value, so a trial request still in flight
+// from an earlier period can never update the count of the current one.
+type trialCounter struct {
+	// value holds the period in its high 32 bits and the count in its low 32 bits.
+	value atomic.Uint64
+}
+
+// reset sets the count to zero and ties the counter to the given period.
+func (c *trialCounter) reset(period uint32) {
+	c.value.Store(uint64(period) << 32)
+}
+
+// add adds delta to the count if the counter still belongs to period and the new count
+// stays within [0, limit].
+//
+// Parameters:
+//   - period: HalfOpen period the trial request was admitted in
+//   - delta: Amount added to the count
+//   - limit: Largest count allowed
+//
+// Returns:
+//   - int32: The new count, or 0 if the counter was not updated
+//   - bool: true if the counter was updated
+func (c *trialCounter) add(period uint32, delta, limit int32) (int32, bool) {
+	for {
+		value := c.value.Load()
+		if uint32(value>>32) != period {
+			return 0, false
+		}
+
+		count := int32(uint32(value)) + delta
+		if count < 0 || count > limit {
+			return 0, false
+		}
+
+		if c.value.CompareAndSwap(value, uint64(period)<<32|uint64(uint32(count))) {
+			return count, true
+		}
+	}
+}
+
+// increment adds one to the count of period, without an upper bound.
+func (c *trialCounter) increment(period uint32) (int32, bool) {
+	return c.add(period, 1, math.MaxInt32)
+}
@@ -0,0 +1,176 @@
+package gendure
+
+import (
+	"crypto/rand"
+	"math"
+	"math/big"
+	"time"
+)
+
+// RandomSource provides the random numbers used by jitter strategies.
+// *math/rand.Rand implements it, so a seeded generator makes jittered delays reproducible in tests.
+// Implementations shared by concurrent retries must be safe for concurrent use.
+type RandomSource interface {
+	// Int63n returns a non-negative random number in [0, n). Called with n > 0.
+	Int63n(n int64) int64
+}
+
+// cryptoRandomSource implements RandomSource with crypto/rand. It is the default random source.
+type cryptoRandomSource struct{}
+
+func (cryptoRandomSource) Int63n(n int64) int64 {
+	value, err := rand.Int(rand.Reader, big.NewInt(n))
+	if err != nil {
+		return 0
+	}
+
+	return value.Int64()
+}
+
+// Jitter randomizes the delay computed by the Backoff so that clients failing together do not
+// retry together. Set on a retry with WithJitter. Implementations must be safe for concurrent use.
+type Jitter interface {
+	// Apply returns the delay to wait before the next attempt.
+	//
+	// Parameters:
+	//   - delay: The delay computed by the Backoff for this attempt
+	//   - previous: The delay waited before the current attempt, zero before the first retry
+	//   - random: The random source set with WithRandomSource
+	Apply(delay, previous time.Duration, random RandomSource) time.Duration
+}
+
+// JitterFunc adapts a function to the Jitter interface.
+type JitterFunc func(delay, previous time.Duration, random RandomSource) time.Duration
+
+// Apply returns f(delay, previous, random).
+func (f JitterFunc) Apply(delay, previous time.Duration, random RandomSource) time.Duration {
+	return f(delay, previous, random)
+}
+
+// NoJitter returns a Jitter waiting exactly the backoff delay.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithJitter(NoJitter()))
+func NoJitter() Jitter {
+	return JitterFunc(func(delay, _ time.Duration, _ RandomSource) time.Duration {
+		return delay
+	})
+}
+
+// NewFullJitter returns a Jitter waiting a random delay between 0 and the backoff delay.
+// Spreads retries the most, as recommended by the AWS Architecture Blog "Exponential Backoff And Jitter".
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithJitter(NewFullJitter()))
+func NewFullJitter() Jitter {
+	return JitterFunc(func(delay, _ time.Duration, random RandomSource) time.Duration {
+		return randomBetween(random, 0, delay)
+	})
+}
+
+// NewEqualJitter returns a Jitter waiting half the backoff delay plus a random delay up to the other half,
+// keeping a minimum wait while still spreading retries.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithJitter(NewEqualJitter()))
+func NewEqualJitter() Jitter {
+	return JitterFunc(func(delay, _ time.Duration, random RandomSource) time.Duration {
+		half := delay / 2
+
+		return half + randomBetween(random, 0, delay-half)
+	})
+}
+
+// NewDecorrelatedJitter returns the decorrelated Jitter from the AWS Architecture Blog
+// "Exponential Backoff And Jitter": each delay is random between base and three times the
+// previous delay, so it ignores the Backoff curve and grows from its own history.
+// Combine it with WithMaxDelay to cap the growth.
+//
+// Parameters:
+//   - base: Minimum delay, also used as the previous delay before the first retry. If < 0, defaults to 0.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback,
+//	    WithJitter(NewDecorrelatedJitter(100*time.Millisecond)),
+//	    WithMaxDelay(10*time.Second),
+//	)
+func NewDecorrelatedJitter(base time.Duration) Jitter {
+	base = max(base, 0)
+
+	return JitterFunc(func(_, previous time.Duration, random RandomSource) time.Duration {
+		previous = max(previous, base)
+
+		return randomBetween(random, base, saturatingDuration(float64(previous)*decorrelatedJitterGrowth))
+	})
+}
+
+// NewProportionalJitter returns a Jitter waiting the backoff delay plus or minus a random
+// fraction of it, such as 0.2 for ±20%.
+//
+// Parameters:
+//   - fraction: Maximum deviation as a fraction of the delay. Clamped to [0, 1].
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithJitter(NewProportionalJitter(0.2)))
+func NewProportionalJitter(fraction float64) Jitter {
+	fraction = min(max(fraction, 0), 1)
+
+	return JitterFunc(func(delay, _ time.Duration, random RandomSource) time.Duration {
+		deviation := time.Duration(float64(delay) * fraction)
+
+		return randomBetween(random, delay-deviation, addDelays(delay, deviation))
+	})
+}
+
+// NewAdditiveJitter returns a Jitter adding a random delay between 0 and maxJitter to the
+// backoff delay. Unlike WithMaxJitterSeconds, the bound can be below a second.
+//
+// Parameters:
+//   - maxJitter: Maximum added delay. If < 0, defaults to 0.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithJitter(NewAdditiveJitter(50*time.Millisecond)))
+func NewAdditiveJitter(maxJitter time.Duration) Jitter {
+	maxJitter = max(maxJitter, 0)
+
+	return JitterFunc(func(delay, _ time.Duration, random RandomSource) time.Duration {
+		return addDelays(delay, randomBetween(random, 0, maxJitter))
+	})
+}
+
+// secondsJitter adds between 0 and seconds-1 whole seconds to the backoff delay.
+// It is the default jitter, configured with randomInt or WithMaxJitterSeconds.
+type secondsJitter struct {
+	seconds int
+}
+
+func (j secondsJitter) Apply(delay, _ time.Duration, random RandomSource) time.Duration {
+	if j.seconds <= 1 {
+		return delay
+	}
+
+	// Keep the whole-second jitter representable so it saturates instead of overflowing
+	seconds := min(int64(j.seconds), int64(maxDuration/time.Second))
+
+	return addDelays(delay, time.Duration(random.Int63n(seconds))*time.Second)
+}
+
+// randomBetween returns a random delay in [low, high], or low if high <= low.
+func randomBetween(random RandomSource, low, high time.Duration) time.Duration {
+	if high <= low {
+		return low
+	}
+
+	span := int64(high - low)
+	if span < math.MaxInt64 {
+		span++
+	}
+
+	return low + time.Duration(random.Int63n(span))
+}
@@ -0,0 +1,235 @@
+//nolint:all // only test
+package gendure_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/marincor/gendure"
+	"github.com/marincor/gendure/clocktest"
+)
+
+// extremeRandom returns the lowest or the highest value of every requested range.
+type extremeRandom struct {
+	highest bool
+}
+
+func (r extremeRandom) Int63n(n int64) int64 {
+	if r.highest {
+		return n - 1
+	}
+
+	return 0
+}
+
+func TestJitterStrategiesBounds(t *testing.T) {
+	t.Parallel()
+
+	delay := time.Second
+	previous := 2 * time.Second
+
+	tests := []struct {
+		name          string
+		jitter        gendure.Jitter
+		lowest        time.Duration
+		highest       time.Duration
+		previousDelay time.Duration
+	}{
+		{name: "none", jitter: gendure.NoJitter(), lowest: delay, highest: delay},
+		{name: "full", jitter: gendure.NewFullJitter(), lowest: 0, highest: delay},
+		{name: "equal", jitter: gendure.NewEqualJitter(), lowest: delay / 2, highest: delay},
+		{
+			name:          "decorrelated",
+			jitter:        gendure.NewDecorrelatedJitter(100 * time.Millisecond),
+			lowest:        100 * time.Millisecond,
+			highest:       3 * previous,
+			previousDelay: previous,
+		},
+		{name: "proportional", jitter: gendure.NewProportionalJitter(0.2), lowest: 800 * time.Millisecond, highest: 1200 * time.Millisecond},
+		{name: "additive", jitter: gendure.NewAdditiveJitter(50 * time.Millisecond), lowest: delay, highest: delay + 50*time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.jitter.Apply(delay, tt.previousDelay, extremeRandom{}); got != tt.lowest {
+				t.Errorf("want lowest delay %s, got %s", tt.lowest, got)
+			}
+
+			if got := tt.jitter.Apply(delay, tt.previousDelay, extremeRandom{highest: true}); got != tt.highest {
+				t.Errorf("want highest delay %s, got %s", tt.highest, got)
+			}
+		})
+	}
+}
+
+func TestDecorrelatedJitterStartsFromBase(t *testing.T) {
+	t.Parallel()
+
+	jitter := gendure.NewDecorrelatedJitter(time.Second)
+
+	if got := jitter.Apply(time.Minute, 0, extremeRandom{highest: true}); got != 3*time.Second {
+		t.Errorf("want 3s before the first retry, got %s", got)
+	}
+}
+
+func TestExponentialBackoffRetryJitterIsReproducible(t *testing.T) {
+	timeline := func(seed int64) []time.Duration {
+		clock := clocktest.NewClock(time.Unix(0, 0))
+
+		exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+			func() (string, error) {
+				return "", errors.New("temporary error")
+			},
+			gendure.WithInitialDelay(100*time.Millisecond),
+			gendure.WithMaxRetries(5),
+			gendure.WithJitter(gendure.NewFullJitter()),
+			gendure.WithRandomSource(rand.New(rand.NewSource(seed))),
+			gendure.WithClock(clock),
+		)
+		if err != nil {
+			t.Fatalf(unexpected, err)
+		}
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			_, _ = exponetionalRetry.Execute(context.Background())
+		}()
+
+		var delays []time.Duration
+
+		for range 4 {
+			clock.BlockUntilTimers(1)
+
+			delay := clock.Timers()[0].Sub(clock.Now())
+			delays = append(delays, delay)
+			clock.Advance(delay)
+		}
+
+		<-done
+
+		return delays
+	}
+
+	first, second := timeline(42), timeline(42)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("delay %d: want same delay for the same seed, got %s and %s", i, first[i], second[i])
+		}
+
+		if first[i] > 100*time.Millisecond<<i {
+			t.Errorf("delay %d: want full jitter at most %s, got %s", i, 100*time.Millisecond<<i, first[i])
+		}
+	}
+}
+
+func TestMaxDelayCapsBackoffBeforeJitter(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	events := make(chan gendure.RetryEvent, 1)
+	maxDelay := time.Second
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			return "", errOperation
+		},
+		gendure.WithInitialDelay(100*time.Millisecond),
+		gendure.WithMaxRetries(12),
+		gendure.WithMaxDelay(maxDelay),
+		gendure.WithJitter(gendure.NewFullJitter()),
+		gendure.WithRandomSource(rand.New(rand.NewSource(1))),
+		gendure.WithClock(clock),
+		gendure.WithOnRetry(func(_ context.Context, event gendure.RetryEvent) {
+			events <- event
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	capped := 0
+
+	for attempt := 0; attempt < 11; attempt++ {
+		event := <-events
+		backoff := min(100*time.Millisecond<<attempt, maxDelay)
+
+		if event.Delay < 0 || event.Delay > backoff {
+			t.Errorf("attempt %d: want delay in [0, %s], got %s", attempt, backoff, event.Delay)
+		}
+
+		if event.Delay-event.Jitter != backoff {
+			t.Errorf("attempt %d: want jitter %s relative to the capped backoff %s, got %s",
+				attempt, event.Delay-backoff, backoff, event.Jitter)
+		}
+
+		if event.Delay == maxDelay {
+			capped++
+		}
+
+		// A zero delay fires right away without a pending timer
+		if event.Delay > 0 {
+			clock.BlockUntilTimers(1)
+			clock.Advance(event.Delay)
+		}
+	}
+
+	if capped > 1 {
+		t.Errorf("want capped delays to stay spread out, got %d delays of exactly %s", capped, maxDelay)
+	}
+
+	if err := <-done; !errors.Is(err, errOperation) {
+		t.Errorf("want the last callback error, got %v", err)
+	}
+}
+
+func TestMaxJitterSecondsDoesNotOverflow(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(0, 0))
+	events := make(chan gendure.RetryEvent, 1)
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			return "", errOperation
+		},
+		gendure.WithMaxRetries(2),
+		gendure.WithMaxJitterSeconds(math.MaxInt),
+		gendure.WithRandomSource(extremeRandom{highest: true}),
+		gendure.WithMaxDelay(time.Hour),
+		gendure.WithClock(clock),
+		gendure.WithOnRetry(func(_ context.Context, event gendure.RetryEvent) {
+			events <- event
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := exponetionalRetry.Execute(context.Background())
+		done <- err
+	}()
+
+	if event := <-events; event.Delay != time.Hour {
+		t.Errorf("want the saturated jitter capped to 1h, got %s", event.Delay)
+	}
+
+	clock.BlockUntilTimers(1)
+	clock.Advance(time.Hour)
+
+	if err := <-done; !errors.Is(err, errOperation) {
+		t.Errorf("want the last callback error, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package gendure
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/marincor/gendure/glogger"
+)
+
+// ErrInvalidOption is wrapped by every validation error returned by the option-based
+// constructors NewCircuitBreakerWithOptions and NewExponentialBackoffRetryWithOptions.
+var ErrInvalidOption = errors.New("gendure: invalid option")
+
+// invalidOption returns a descriptive validation error wrapping ErrInvalidOption.
+func invalidOption(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{ErrInvalidOption}, args...)...)
+}
+
+// sharedConfig holds the settings common to circuit breakers and retries.
+type sharedConfig struct {
+	// logger is the optional logger instance. If nil, logging is disabled.
+	logger glogger.GLogger
+
+	// recoverPanics converts operation panics into *PanicError failures.
+	recoverPanics bool
+
+	// repanic re-panics with the *PanicError once the failure has been accounted.
+	repanic bool
+
+	// clock provides the current time and timers.
+	clock Clock
+}
+
+// defaultSharedConfig returns the shared settings used when no options are supplied.
+func defaultSharedConfig() sharedConfig {
+	return sharedConfig{clock: realClock{}}
+}
+
+// Option configures a setting shared by circuit breakers and retries.
+// An Option can be passed wherever a CircuitBreakerOption or a RetryOption is expected.
+type Option func(cfg *sharedConfig) error
+
+func (o Option) applyCircuitBreaker(cfg *circuitBreakerConfig) error {
+	return o(&cfg.sharedConfig)
+}
+
+func (o Option) applyRetry(cfg *retryConfig) error {
+	return o(&cfg.sharedConfig)
+}
+
+// WithLogger sets the logger used for debugging and monitoring.
+//
+// Parameters:
+//   - logger: Logger instance. Pass nil to disable logging.
+//
+// Example:
+//
+//	cb, err := NewCircuitBreakerWithOptions[string](WithLogger(glogger.New()))
+func WithLogger(logger glogger.GLogger) Option {
+	return func(cfg *sharedConfig) error {
+		cfg.logger = logger
+
+		return nil
+	}
+}
+
+// WithPanicRecovery recovers panics raised by the protected operation and converts them into
+// a *PanicError carrying the panic value and stack trace. The panic counts as a failure: it can
+// open the circuit breaker and is retried by the retry like any other failed attempt.
+//
+// Without this option, a circuit breaker still records the panic as a failure, releasing its
+// HalfOpen trial slot, before letting it propagate, and a retry lets it propagate immediately.
+//
+// Parameters:
+//   - repanic: If true, panic again with the *PanicError once the failure has been accounted:
+//     immediately for a circuit breaker, and when giving up for a retry.
+//     If false, the *PanicError is returned as the operation error.
+//
+// Example:
+//
+//	cb, err := NewCircuitBreakerWithOptions[string](WithPanicRecovery(false))
+func WithPanicRecovery(repanic bool) Option {
+	return func(cfg *sharedConfig) error {
+		cfg.recoverPanics = true
+		cfg.repanic = repanic
+
+		return nil
+	}
+}
+
+// WithClock sets the clock used to measure call durations, recovery timeouts and sliding
+// windows, and to wait between retries. Useful to control time in tests.
+//
+// Parameters:
+//   - clock: Clock implementation. Cannot be nil. Defaults to the time package.
+//
+// Example:
+//
+//	clock := clocktest.NewClock(time.Now())
+//	cb, err := NewCircuitBreakerWithOptions[string](WithClock(clock))
+func WithClock(clock Clock) Option {
+	return func(cfg *sharedConfig) error {
+		if clock == nil {
+			return invalidOption("clock cannot be nil")
+		}
+
+		cfg.clock = clock
+
+		return nil
+	}
+}
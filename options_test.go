@@ -0,0 +1,144 @@
+//nolint:all // only test
+package gendure_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marincor/gendure"
+	"github.com/marincor/gendure/glogger"
+)
+
+func TestNewCircuitBreakerWithOptions(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker, err := gendure.NewCircuitBreakerWithOptions[int](
+		gendure.WithFailureThreshold(2),
+		gendure.WithRecoveryTimeout(time.Second),
+		gendure.WithLogger(glogger.New()),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, _ = cirbuitBreaker.Do(context.Background(), func(ctx context.Context) (int, error) {
+			return 0, errOperation
+		})
+	}
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open, got %d", state)
+	}
+}
+
+func TestNewCircuitBreakerWithOptionsReportsInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker, err := gendure.NewCircuitBreakerWithOptions[int](
+		gendure.WithFailureThreshold(0),
+		gendure.WithRecoveryTimeout(-time.Second),
+		gendure.WithFailureRateThreshold(150),
+		gendure.WithHalfOpenCalls(2, 3),
+	)
+	if cirbuitBreaker != nil {
+		t.Error("expected no circuit breaker on invalid settings")
+	}
+
+	if !errors.Is(err, gendure.ErrInvalidOption) {
+		t.Fatalf("expected ErrInvalidOption, got %v", err)
+	}
+
+	for _, message := range []string{"failure threshold", "recovery timeout", "failure rate threshold", "half-open"} {
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("expected error to mention %q, got %v", message, err)
+		}
+	}
+
+	_, err = gendure.NewCircuitBreakerWithOptions[int](
+		gendure.WithCountSlidingWindow(10),
+		gendure.WithMinimumCalls(20),
+	)
+	if !errors.Is(err, gendure.ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption for minimum calls above window size, got %v", err)
+	}
+}
+
+func TestNewCircuitBreakerSkipsInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	cirbuitBreaker := gendure.NewCircuitBreaker[int](
+		1,
+		time.Second,
+		nil,
+		gendure.WithCountSlidingWindow(0),
+		gendure.WithMinimumCalls(500),
+		nil,
+	)
+
+	_, _ = cirbuitBreaker.Do(context.Background(), func(ctx context.Context) (int, error) {
+		return 0, errOperation
+	})
+
+	if state := cirbuitBreaker.GetState(); state != gendure.Open {
+		t.Errorf("expected state to be Open, got %d", state)
+	}
+}
+
+func TestNewExponentialBackoffRetryWithOptions(t *testing.T) {
+	t.Parallel()
+
+	callCount := 0
+
+	retry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			callCount++
+			if callCount < 3 {
+				return "", errOperation
+			}
+
+			return success, nil
+		},
+		gendure.WithInitialDelay(time.Millisecond),
+		gendure.WithMaxRetries(3),
+		gendure.WithMultiplier(2),
+		gendure.WithMaxJitterSeconds(1),
+		gendure.WithLogger(nil),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	result, err := retry.Execute(context.Background())
+	if err != nil {
+		t.Errorf(errorWantSuccessGotError, err)
+	}
+
+	if result != success {
+		t.Errorf(errorWantSuccessGot, result)
+	}
+}
+
+func TestNewExponentialBackoffRetryWithOptionsReportsInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	_, err := gendure.NewExponentialBackoffRetryWithOptions[int](
+		nil,
+		gendure.WithInitialDelay(0),
+		gendure.WithMaxRetries(-1),
+		gendure.WithRetryIf(nil),
+		gendure.WithClock(nil),
+	)
+	if !errors.Is(err, gendure.ErrInvalidOption) {
+		t.Fatalf("expected ErrInvalidOption, got %v", err)
+	}
+
+	for _, message := range []string{"callback", "initial delay", "max retries", "retry predicate", "clock"} {
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("expected error to mention %q, got %v", message, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package gendure
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned in place of an operation error when the operation panicked and panic
+// recovery is enabled with WithPanicRecovery. A recovered panic always counts as a failure,
+// regardless of error classification.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the panicking goroutine, captured when the panic was recovered.
+	Stack []byte
+}
+
+// newPanicError captures the stack of the panicking goroutine.
+// Must be called from the deferred function that recovered the panic.
+func newPanicError(value any) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+// Error returns the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("gendure: operation panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is and errors.As match it.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
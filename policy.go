@@ -0,0 +1,32 @@
+package gendure
+
+import "context"
+
+// Policy is the common abstraction over the resilience patterns of this package.
+// Both *CircuitBreaker[T] and ExponentialBackoffRetry[T] implement it, so callers can depend
+// on Policy, swap one pattern for another, or replace it with a stub in tests.
+//
+// Type Parameters:
+//   - T: The return type of the protected operation
+//
+// Example:
+//
+//	type UserClient struct {
+//	    policy gendure.Policy[*User]
+//	}
+//
+//	func (c *UserClient) Get(ctx context.Context, id string) (*User, error) {
+//	    return c.policy.Do(ctx, func(ctx context.Context) (*User, error) {
+//	        return c.fetch(ctx, id)
+//	    })
+//	}
+type Policy[T any] interface {
+	// Do runs operation under the policy's protection and returns its result.
+	// The context is passed to the operation so it can abort in-flight work on cancellation.
+	Do(ctx context.Context, operation func(ctx context.Context) (T, error)) (T, error)
+}
+
+var (
+	_ Policy[any] = (*CircuitBreaker[any])(nil)
+	_ Policy[any] = ExponentialBackoffRetry[any]{}
+)
@@ -0,0 +1,84 @@
+//nolint:all // only test
+package gendure_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marincor/gendure"
+)
+
+type policyClient struct {
+	breaker *gendure.CircuitBreaker[int]
+	policy  gendure.Policy[int]
+}
+
+func callThroughPolicy(ctx context.Context, policy gendure.Policy[int], operation func(ctx context.Context) (int, error)) (int, error) {
+	return policy.Do(ctx, operation)
+}
+
+func TestPolicyImplementations(t *testing.T) {
+	t.Parallel()
+
+	breaker := gendure.NewCircuitBreaker[int](3, time.Second, nil)
+	retry := gendure.NewExponentialBackoffRetry(
+		func() (int, error) {
+			t.Error("should not call the bound callback from Do")
+
+			return 0, nil
+		},
+		time.Millisecond,
+		3,
+		2,
+		1,
+		nil,
+	)
+
+	client := policyClient{breaker: breaker, policy: retry}
+
+	policies := []gendure.Policy[int]{client.breaker, client.policy}
+	for i, policy := range policies {
+		calls := 0
+		retried := i == 1
+
+		result, err := callThroughPolicy(context.Background(), policy, func(ctx context.Context) (int, error) {
+			calls++
+			if calls < 2 && retried {
+				return 0, errOperation
+			}
+
+			return 42, nil
+		})
+		if err != nil {
+			t.Errorf(unexpected, err)
+		}
+
+		if result != 42 {
+			t.Errorf("expected 42, got %d", result)
+		}
+	}
+}
+
+func TestCircuitBreakerDoReturnsErrCircuitOpen(t *testing.T) {
+	t.Parallel()
+
+	breaker := gendure.NewCircuitBreaker[int](1, time.Second, nil)
+
+	_, err := breaker.Do(context.Background(), func(ctx context.Context) (int, error) {
+		return 0, errOperation
+	})
+	if !errors.Is(err, errOperation) {
+		t.Errorf("expected operation error, got %v", err)
+	}
+
+	_, err = breaker.Do(context.Background(), func(ctx context.Context) (int, error) {
+		t.Error("should not call operation when in an open state")
+
+		return 0, nil
+	})
+	if !errors.Is(err, gendure.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package gendure
+
+import "time"
+
+// A retry is recorded in the budget window as a failed call and a successful call as a
+// successful one, so the window's failure count is the number of recent retries.
+const (
+	budgetSuccess = outcomeRecorded
+	budgetRetry   = outcomeRecorded | outcomeFailure
+)
+
+// RetryBudget caps retry amplification across every retry sharing it.
+// Within a sliding time window it allows a fraction of the recent successful calls to be retried,
+// plus a minimum number of retries per second so callers with little traffic can still retry.
+// Once the budget is spent, retries give up with ErrRetryBudgetExhausted instead of adding load
+// to a failing dependency. This is the retry budget of Finagle and gRPC.
+//
+// A RetryBudget is safe for concurrent use and is meant to be shared by every retry calling the
+// same dependency, through WithRetryBudget.
+//
+// Example:
+//
+//	// Retry at most 20% of recent successful calls, plus 10 retries per second
+//	budget := gendure.NewRetryBudget(0.2, 10, 10*time.Second)
+//
+//	policy, err := gendure.NewRetryPolicy(
+//	    gendure.WithMaxRetries(3),
+//	    gendure.WithRetryBudget(budget),
+//	)
+type RetryBudget struct {
+	// ratio is the number of retries allowed per successful call in the window.
+	ratio float64
+
+	// reserve is the number of retries always allowed in the window.
+	reserve float64
+
+	// window aggregates successful calls and retries per second.
+	window *timeWindow
+}
+
+// NewRetryBudget creates a retry budget shared by the retries calling one dependency.
+//
+// Parameters:
+//   - ratio: Retries allowed per successful call in the window, such as 0.2 for 20%. If < 0, defaults to 0.
+//   - minRetriesPerSecond: Retries allowed per second of the window regardless of successes.
+//     If < 0, defaults to 0.
+//   - window: How long successful calls and retries are remembered, rounded up to whole seconds.
+//     If < 1s, defaults to 10s.
+//
+// Returns:
+//   - *RetryBudget: A budget ready to be passed to WithRetryBudget
+//
+// Example:
+//
+//	budget := NewRetryBudget(0.2, 10, 10*time.Second)
+func NewRetryBudget(ratio float64, minRetriesPerSecond int, window time.Duration) *RetryBudget {
+	if window < time.Second {
+		window = defaultRetryBudgetWindow
+	}
+
+	retryBudget := &RetryBudget{
+		ratio:  max(ratio, 0),
+		window: newTimeWindow(window),
+	}
+
+	retryBudget.reserve = float64(max(minRetriesPerSecond, 0) * len(retryBudget.window.buckets))
+
+	return retryBudget
+}
+
+// recordSuccess deposits a successful call into the budget.
+func (b *RetryBudget) recordSuccess(now time.Time) {
+	b.window.record(budgetSuccess, now)
+}
+
+// tryRetry withdraws one retry from the budget.
+// The retry is recorded first and taken back if it overdraws the budget, so concurrent
+// callers never spend more than the budget allows.
+//
+// Returns:
+//   - bool: true if the retry is allowed
+func (b *RetryBudget) tryRetry(now time.Time) bool {
+	bucket := b.window.bucket(now.Unix())
+	bucket.apply(budgetRetry, 1)
+
+	snapshot := b.window.snapshot(now)
+	successes := snapshot.calls - snapshot.failures
+
+	if float64(snapshot.failures) <= b.reserve+b.ratio*float64(successes) {
+		return true
+	}
+
+	bucket.apply(budgetRetry, -1)
+
+	return false
+}
@@ -0,0 +1,124 @@
+//nolint:all // only test
+package gendure_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marincor/gendure"
+	"github.com/marincor/gendure/clocktest"
+)
+
+func newBudgetedRetry(t *testing.T, budget *gendure.RetryBudget, clock gendure.Clock, calls *int, failures int) gendure.ExponentialBackoffRetry[string] {
+	t.Helper()
+
+	exponetionalRetry, err := gendure.NewExponentialBackoffRetryWithOptions(
+		func() (string, error) {
+			*calls++
+			if *calls <= failures {
+				return "", errOperation
+			}
+
+			return success, nil
+		},
+		gendure.WithMaxRetries(3),
+		gendure.WithBackoff(gendure.NewConstantBackoff(0)),
+		gendure.WithJitter(gendure.NoJitter()),
+		gendure.WithRetryBudget(budget),
+		gendure.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	return exponetionalRetry
+}
+
+func TestRetryBudgetExhausted(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(1000, 0))
+	budget := gendure.NewRetryBudget(0, 0, time.Second)
+
+	calls := 0
+	exponetionalRetry := newBudgetedRetry(t, budget, clock, &calls, 1)
+
+	_, err := exponetionalRetry.Execute(context.Background())
+
+	var retryErr *gendure.RetryError
+	if !errors.As(err, &retryErr) || !errors.Is(err, gendure.ErrRetryBudgetExhausted) || !errors.Is(err, errOperation) {
+		t.Errorf("want a RetryError with ErrRetryBudgetExhausted and the callback error, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf(errorWant1CallGot, calls)
+	}
+}
+
+func TestRetryBudgetMinimumRetriesPerSecond(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(1000, 0))
+	budget := gendure.NewRetryBudget(0, 1, time.Second)
+
+	calls := 0
+	first := newBudgetedRetry(t, budget, clock, &calls, 1)
+
+	result, err := first.Execute(context.Background())
+	if err != nil || result != success || calls != 2 {
+		t.Fatalf("want success after 2 calls, got '%s' and %v after %d calls", result, err, calls)
+	}
+
+	calls = 0
+	second := newBudgetedRetry(t, budget, clock, &calls, 1)
+
+	_, err = second.Execute(context.Background())
+	if !errors.Is(err, gendure.ErrRetryBudgetExhausted) || calls != 1 {
+		t.Errorf("want ErrRetryBudgetExhausted after 1 call, got %v after %d calls", err, calls)
+	}
+
+	// The allowance is restored once the window slides past the spent retry
+	clock.Advance(time.Second)
+
+	calls = 0
+
+	result, err = second.Execute(context.Background())
+	if err != nil || result != success || calls != 2 {
+		t.Errorf("want success after 2 calls, got '%s' and %v after %d calls", result, err, calls)
+	}
+}
+
+func TestRetryBudgetReplenishedBySuccesses(t *testing.T) {
+	clock := clocktest.NewClock(time.Unix(1000, 0))
+	budget := gendure.NewRetryBudget(0.5, 0, 10*time.Second)
+
+	calls := 0
+	healthy := newBudgetedRetry(t, budget, clock, &calls, 0)
+
+	for range 4 {
+		if _, err := healthy.Execute(context.Background()); err != nil {
+			t.Fatalf(errorWantSuccessGotError, err)
+		}
+	}
+
+	// 4 successful calls at a 50% ratio allow 2 retries
+	calls = 0
+	failing := newBudgetedRetry(t, budget, clock, &calls, 10)
+
+	_, err := failing.Execute(context.Background())
+	if !errors.Is(err, gendure.ErrRetriesExhausted) || calls != 3 {
+		t.Errorf("want ErrRetriesExhausted after 3 calls, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+
+	_, err = failing.Execute(context.Background())
+	if !errors.Is(err, gendure.ErrRetryBudgetExhausted) || calls != 1 {
+		t.Errorf("want ErrRetryBudgetExhausted after 1 call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryBudgetInvalidOption(t *testing.T) {
+	_, err := gendure.NewRetryPolicy(gendure.WithRetryBudget(nil))
+	if !errors.Is(err, gendure.ErrInvalidOption) {
+		t.Errorf("want ErrInvalidOption for a nil budget, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package gendure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRetriesExhausted is matched by the *RetryError returned when every attempt failed.
+var ErrRetriesExhausted = errors.New("gendure: retries exhausted")
+
+// ErrMaxElapsedTime is matched by the *RetryError returned when the retry stops because
+// the next delay would exceed the budget set with WithMaxElapsedTime.
+var ErrMaxElapsedTime = errors.New("gendure: retry max elapsed time exceeded")
+
+// ErrDeadlineWouldExceed is matched by the *RetryError returned when the retry stops early because
+// the next attempt could not start before the context deadline. It wraps context.DeadlineExceeded,
+// so deadline checks keep working while the *RetryError still reports the callback errors.
+var ErrDeadlineWouldExceed = fmt.Errorf("gendure: next attempt would start after the context deadline: %w",
+	context.DeadlineExceeded)
+
+// ErrRetryBudgetExhausted is matched by the *RetryError returned when the retry stops because
+// the RetryBudget set with WithRetryBudget allows no more retries.
+var ErrRetryBudgetExhausted = errors.New("gendure: retry budget exhausted")
+
+// ErrRetryableResult is returned together with the last result when every attempt returned
+// a result that the predicate set with WithRetryOnResult asked to retry.
+var ErrRetryableResult = errors.New("gendure: retries exhausted on a retryable result")
+
+// PermanentError marks an error that must not be retried. Returned by Permanent.
+// The retry stops immediately and returns the wrapped error, not the PermanentError.
+type PermanentError struct {
+	// Err is the original error.
+	Err error
+}
+
+// Permanent wraps err so that the retry stops immediately instead of retrying it.
+// Callbacks return it for failures that cannot succeed on a later attempt, such as
+// a 400 Bad Request or a validation error.
+//
+// Parameters:
+//   - err: The original error. If nil, Permanent returns nil.
+//
+// Returns:
+//   - error: A *PermanentError wrapping err, or nil
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusBadRequest {
+//	    return nil, Permanent(fmt.Errorf("invalid request: %s", resp.Status))
+//	}
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PermanentError{Err: err}
+}
+
+// Error returns the message of the original error.
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// unwrapPermanent returns the original error if err is or wraps a *PermanentError,
+// or err unchanged otherwise.
+func unwrapPermanent(err error) error {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return permanent.Err
+	}
+
+	return err
+}
+
+// RetryAfterHint is implemented by errors carrying a delay requested by the server, such as the
+// Retry-After header of a 429 or 503 response. When an attempt fails with such an error, the retry
+// waits at least that delay before the next attempt (see WithRetryAfterMode and WithMaxRetryAfter).
+type RetryAfterHint interface {
+	// RetryAfter returns the delay requested before the next attempt. Values <= 0 are ignored.
+	RetryAfter() time.Duration
+}
+
+// RetryAfterMode tells how a delay hint from an error implementing RetryAfterHint is applied.
+type RetryAfterMode int
+
+const (
+	// RetryAfterFloor waits the longer of the computed delay and the hint.
+	RetryAfterFloor RetryAfterMode = iota
+
+	// RetryAfterOverride waits exactly the hint, even if the computed delay is longer.
+	RetryAfterOverride
+
+	// RetryAfterIgnore ignores hints and always waits the computed delay.
+	RetryAfterIgnore
+)
+
+// RetryAfterError is an error carrying a delay hint. Returned by RetryAfter.
+type RetryAfterError struct {
+	// Err is the original error.
+	Err error
+
+	// Delay is the delay requested before the next attempt.
+	Delay time.Duration
+}
+
+// RetryAfter wraps err with a delay hint, such as one parsed from a Retry-After header,
+// so that the retry waits at least delay before the next attempt.
+//
+// Parameters:
+//   - err: The original error. If nil, RetryAfter returns nil.
+//   - delay: The delay requested before the next attempt
+//
+// Returns:
+//   - error: A *RetryAfterError wrapping err, or nil
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusTooManyRequests {
+//	    seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+//	    return nil, RetryAfter(errRateLimited, time.Duration(seconds)*time.Second)
+//	}
+func RetryAfter(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	return &RetryAfterError{Err: err, Delay: delay}
+}
+
+// Error returns the message of the original error.
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter returns the delay requested before the next attempt.
+func (e *RetryAfterError) RetryAfter() time.Duration {
+	return e.Delay
+}
+
+// AttemptFailure records a failed attempt of a retry.
+type AttemptFailure struct {
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+
+	// Err is the error returned by the attempt, ErrRetryableResult if its result was retried,
+	// or a *PanicError if it panicked with panic recovery enabled.
+	Err error
+
+	// StartedAt is the time the attempt started.
+	StartedAt time.Time
+
+	// Duration is how long the attempt ran.
+	Duration time.Duration
+
+	// Delay is the time waited after the attempt, jitter included. Zero for the last attempt.
+	Delay time.Duration
+}
+
+// RetryError is returned when a retry gives up after failed attempts: every attempt failed,
+// the elapsed time budget ran out, the context deadline would pass before the next attempt,
+// or the retry budget is spent. It keeps the failure history: errors.Is and errors.As match
+// the reason as well as the error of any attempt.
+//
+// Errors that stop the retry on their own, such as those wrapped with Permanent or rejected by
+// WithRetryIf, and context errors are returned as is instead.
+type RetryError struct {
+	// Reason is why the retry gave up: ErrRetriesExhausted, ErrMaxElapsedTime, ErrDeadlineWouldExceed
+	// or ErrRetryBudgetExhausted.
+	Reason error
+
+	// Failures lists every failed attempt, in order.
+	Failures []AttemptFailure
+}
+
+// Error returns the reason, the number of attempts and the last attempt error.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%s after %d attempts: %v", e.Reason, len(e.Failures), e.Last())
+}
+
+// Last returns the error of the last attempt, or nil if no attempt was made.
+func (e *RetryError) Last() error {
+	if len(e.Failures) == 0 {
+		return nil
+	}
+
+	return e.Failures[len(e.Failures)-1].Err
+}
+
+// Unwrap returns the reason followed by the error of every attempt.
+func (e *RetryError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures)+1)
+	errs = append(errs, e.Reason)
+
+	for _, failure := range e.Failures {
+		errs = append(errs, failure.Err)
+	}
+
+	return errs
+}
@@ -0,0 +1,51 @@
+package gendure
+
+import (
+	"context"
+	"time"
+)
+
+// RetryEvent describes a step of a retry. Delivered to hooks registered with WithOnRetry,
+// WithOnGiveUp and WithOnSuccess.
+type RetryEvent struct {
+	// Attempt is the number of attempts made so far, starting at 1.
+	// Zero when the retry gave up before the first attempt because the context was done.
+	Attempt int
+
+	// Err is the error of the last attempt (OnRetry), or the error returned by the retry (OnGiveUp).
+	// Nil on success.
+	Err error
+
+	// Delay is the time waited before the next attempt, jitter included. Zero on give up and success.
+	Delay time.Duration
+
+	// Jitter is the part of Delay added, or removed when negative, by the jitter strategy.
+	// Zero when a server delay hint set Delay (see RetryAfterHint).
+	Jitter time.Duration
+
+	// Elapsed is the time since the first attempt started.
+	Elapsed time.Duration
+}
+
+// RetryHook is called with a retry event and the context passed to the retry.
+// Hooks run synchronously on the retrying goroutine and must not block.
+type RetryHook func(ctx context.Context, event RetryEvent)
+
+// retryHooks holds the hooks registered on a retry.
+type retryHooks struct {
+	// onRetry is called before each sleep between attempts.
+	onRetry []RetryHook
+
+	// onGiveUp is called when the retry returns an error.
+	onGiveUp []RetryHook
+
+	// onSuccess is called when an attempt succeeds.
+	onSuccess []RetryHook
+}
+
+// callHooks invokes every hook with the event.
+func callHooks(ctx context.Context, hooks []RetryHook, event RetryEvent) {
+	for _, hook := range hooks {
+		hook(ctx, event)
+	}
+}
@@ -0,0 +1,552 @@
+package gendure
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryOption configures an exponential backoff retry.
+// Options are applied in order; later options override earlier ones.
+// Every Option shared with circuit breakers, such as WithLogger, is also a RetryOption.
+type RetryOption interface {
+	applyRetry(cfg *retryConfig) error
+}
+
+// retryOptionFunc adapts a function to the RetryOption interface.
+type retryOptionFunc func(cfg *retryConfig) error
+
+func (f retryOptionFunc) applyRetry(cfg *retryConfig) error {
+	return f(cfg)
+}
+
+// retryConfig holds the settings collected from RetryOption values.
+type retryConfig struct {
+	sharedConfig
+
+	// initialDelay is the base delay duration for the first retry attempt.
+	initialDelay time.Duration
+
+	// maxRetries is the maximum number of executions, including the initial attempt.
+	maxRetries int
+
+	// multiplier is the factor by which the delay increases with each attempt.
+	multiplier int
+
+	// randomInt is the upper bound (in seconds) for random jitter.
+	randomInt int
+
+	// attemptTimeout bounds the duration of each attempt. Zero means no per-attempt limit.
+	attemptTimeout time.Duration
+
+	// retryIf reports whether a failed attempt may be retried. Nil retries every error.
+	retryIf func(err error) bool
+
+	// retryOnResult holds the func(T) bool predicate set by WithRetryOnResult.
+	// Stored untyped because options are shared across result types.
+	retryOnResult any
+
+	// jitter randomizes the backoff delay. Nil adds between 0 and randomInt-1 seconds.
+	jitter Jitter
+
+	// random is the random source used by jitter.
+	random RandomSource
+
+	// backoff computes the delay between attempts. Nil uses an exponential backoff
+	// from initialDelay and multiplier.
+	backoff Backoff
+
+	// maxDelay caps the backoff delay before jitter, and the jittered delay. Zero means no cap.
+	maxDelay time.Duration
+
+	// maxElapsedTime bounds the total retry time. Zero means no budget other than the context.
+	maxElapsedTime time.Duration
+
+	// hooks are called before each sleep, on give up and on success.
+	hooks retryHooks
+
+	// retryAfterMode tells how delay hints from errors implementing RetryAfterHint are applied.
+	retryAfterMode RetryAfterMode
+
+	// maxRetryAfter caps delay hints. Zero means no cap.
+	maxRetryAfter time.Duration
+
+	// budget is the shared retry budget consulted before each retry. Nil means no budget.
+	budget *RetryBudget
+}
+
+// defaultRetryConfig returns the configuration used when no options are supplied.
+func defaultRetryConfig() retryConfig {
+	return retryConfig{
+		sharedConfig: defaultSharedConfig(),
+		initialDelay: defaultInitialDelay,
+		maxRetries:   defaultMaxRetries,
+		multiplier:   defaultMultiplier,
+		randomInt:    defaultRandomInt,
+		random:       cryptoRandomSource{},
+	}
+}
+
+// newRetryConfig applies opts over the defaults and returns every invalid setting joined together.
+func newRetryConfig(opts []RetryOption) (retryConfig, error) {
+	cfg := defaultRetryConfig()
+
+	var errs []error
+
+	for _, opt := range opts {
+		if opt == nil {
+			errs = append(errs, invalidOption("retry option cannot be nil"))
+
+			continue
+		}
+
+		if err := opt.applyRetry(&cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return cfg, errors.Join(errs...)
+}
+
+// newBackoff returns the configured backoff, or the exponential backoff
+// from initialDelay and multiplier if none was set.
+func (cfg retryConfig) newBackoff() Backoff {
+	if cfg.backoff != nil {
+		return cfg.backoff
+	}
+
+	return NewExponentialBackoff(cfg.initialDelay, float64(cfg.multiplier))
+}
+
+// newJitter returns the configured jitter, or the whole-second jitter from randomInt if none was set.
+func (cfg retryConfig) newJitter() Jitter {
+	if cfg.jitter != nil {
+		return cfg.jitter
+	}
+
+	return secondsJitter{seconds: cfg.randomInt}
+}
+
+// WithInitialDelay sets the delay before the first retry. Later delays grow from it.
+//
+// Parameters:
+//   - delay: Starting delay duration. Must be greater than 0. Defaults to 100ms.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithInitialDelay(500*time.Millisecond))
+func WithInitialDelay(delay time.Duration) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if delay <= 0 {
+			return invalidOption("initial delay must be greater than 0, got %s", delay)
+		}
+
+		cfg.initialDelay = delay
+
+		return nil
+	})
+}
+
+// WithMaxRetries sets the maximum number of executions, including the initial attempt.
+//
+// Parameters:
+//   - maxRetries: Maximum number of attempts. Must be greater than 0. Defaults to 3.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithMaxRetries(5))
+func WithMaxRetries(maxRetries int) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if maxRetries <= 0 {
+			return invalidOption("max retries must be greater than 0, got %d", maxRetries)
+		}
+
+		cfg.maxRetries = maxRetries
+
+		return nil
+	})
+}
+
+// WithMultiplier sets the exponential growth factor of the delay between attempts.
+//
+// Parameters:
+//   - multiplier: Growth factor. Must be greater than 0. Defaults to 2.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithMultiplier(3))
+func WithMultiplier(multiplier int) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if multiplier <= 0 {
+			return invalidOption("multiplier must be greater than 0, got %d", multiplier)
+		}
+
+		cfg.multiplier = multiplier
+
+		return nil
+	})
+}
+
+// WithMaxJitterSeconds sets the upper bound of the random jitter added to each delay.
+// A random value between 0 and seconds-1 seconds is added.
+//
+// Parameters:
+//   - seconds: Jitter upper bound in seconds. Must be greater than 0. Defaults to 1 (no jitter).
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithMaxJitterSeconds(3))
+func WithMaxJitterSeconds(seconds int) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if seconds <= 0 {
+			return invalidOption("max jitter seconds must be greater than 0, got %d", seconds)
+		}
+
+		cfg.randomInt = seconds
+
+		return nil
+	})
+}
+
+// WithAttemptTimeout bounds the duration of each attempt. Context-aware callbacks receive an
+// attempt-scoped context cancelled when the timeout elapses or when the attempt returns, so results
+// tied to it, such as a response body, must be fully consumed inside the callback. The attempt counts
+// as failed if the callback returns an error. The overall deadline is still set by the context passed to Execute.
+// As for any context deadline, the timeout is measured in real time, not with the Clock set by WithClock.
+//
+// Parameters:
+//   - timeout: Maximum duration of a single attempt. Must be greater than 0. Defaults to no limit.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryContext(callback, WithAttemptTimeout(2*time.Second))
+func WithAttemptTimeout(timeout time.Duration) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if timeout <= 0 {
+			return invalidOption("attempt timeout must be greater than 0, got %s", timeout)
+		}
+
+		cfg.attemptTimeout = timeout
+
+		return nil
+	})
+}
+
+// WithRetryIf sets a predicate deciding whether a failed attempt is retried. Errors rejected by
+// the predicate are returned immediately. Errors wrapped with Permanent are never retried,
+// whatever the predicate says.
+//
+// Parameters:
+//   - retryIf: Predicate returning true for retryable errors. Cannot be nil. Defaults to retrying every error.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback,
+//	    WithRetryIf(func(err error) bool {
+//	        return !errors.Is(err, ErrValidation)
+//	    }),
+//	)
+func WithRetryIf(retryIf func(err error) bool) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if retryIf == nil {
+			return invalidOption("retry predicate cannot be nil")
+		}
+
+		cfg.retryIf = retryIf
+
+		return nil
+	})
+}
+
+// WithRetryOnResult sets a predicate deciding whether a successful attempt is retried based on
+// its result, for dependencies answering with a "pending" or "try later" payload instead of an
+// error. If every attempt returns such a result, the last one is returned with ErrRetryableResult.
+//
+// Type Parameters:
+//   - T: The result type of the retry. Must match the retry's type parameter; a mismatch is
+//     reported by the option-based constructors and ignored by NewExponentialBackoffRetry.
+//
+// Parameters:
+//   - retryOnResult: Predicate returning true for results to retry. Cannot be nil.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(fetchJob,
+//	    WithRetryOnResult(func(job Job) bool {
+//	        return job.Status == "pending"
+//	    }),
+//	)
+func WithRetryOnResult[T any](retryOnResult func(result T) bool) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if retryOnResult == nil {
+			return invalidOption("result predicate cannot be nil")
+		}
+
+		cfg.retryOnResult = retryOnResult
+
+		return nil
+	})
+}
+
+// WithMaxDelay caps the delay between attempts, so exponential growth levels off instead of
+// reaching minutes or hours on later attempts. The backoff delay is capped before the jitter
+// is applied, as in full jitter's rand(0, min(maxDelay, backoff)), so capped delays stay spread
+// out; the jittered delay is capped too.
+//
+// Parameters:
+//   - maxDelay: Maximum delay between attempts. Must be greater than 0. Defaults to no cap.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithMaxRetries(10), WithMaxDelay(30*time.Second))
+func WithMaxDelay(maxDelay time.Duration) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if maxDelay <= 0 {
+			return invalidOption("max delay must be greater than 0, got %s", maxDelay)
+		}
+
+		cfg.maxDelay = maxDelay
+
+		return nil
+	})
+}
+
+// WithMaxElapsedTime sets a budget for the whole retry, measured from the first attempt.
+// The retry stops as soon as the next delay would end past the budget, returning an error
+// wrapping ErrMaxElapsedTime and the last attempt error, instead of sleeping in vain.
+//
+// Parameters:
+//   - maxElapsedTime: Total retry time budget. Must be greater than 0. Defaults to no budget.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithMaxElapsedTime(time.Minute))
+func WithMaxElapsedTime(maxElapsedTime time.Duration) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if maxElapsedTime <= 0 {
+			return invalidOption("max elapsed time must be greater than 0, got %s", maxElapsedTime)
+		}
+
+		cfg.maxElapsedTime = maxElapsedTime
+
+		return nil
+	})
+}
+
+// WithBackoff selects the delay curve between attempts, replacing the exponential backoff
+// built from WithInitialDelay and WithMultiplier. Jitter, WithMaxDelay and WithMaxElapsedTime
+// still apply on top of it.
+//
+// Parameters:
+//   - backoff: Delay strategy such as NewConstantBackoff, NewLinearBackoff, NewExponentialBackoff,
+//     NewFibonacciBackoff, NewPolynomialBackoff or a BackoffFunc. Cannot be nil.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback,
+//	    WithBackoff(NewExponentialBackoff(100*time.Millisecond, 1.5)),
+//	)
+func WithBackoff(backoff Backoff) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if backoff == nil {
+			return invalidOption("backoff cannot be nil")
+		}
+
+		cfg.backoff = backoff
+
+		return nil
+	})
+}
+
+// WithJitter selects how the backoff delay is randomized, replacing the whole-second jitter
+// set with WithMaxJitterSeconds. WithMaxDelay still caps the jittered delay.
+//
+// Parameters:
+//   - jitter: Jitter strategy such as NoJitter, NewFullJitter, NewEqualJitter, NewDecorrelatedJitter,
+//     NewProportionalJitter, NewAdditiveJitter or a JitterFunc. Cannot be nil.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithJitter(NewFullJitter()))
+func WithJitter(jitter Jitter) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if jitter == nil {
+			return invalidOption("jitter cannot be nil")
+		}
+
+		cfg.jitter = jitter
+
+		return nil
+	})
+}
+
+// WithRandomSource sets the random source used by jitter, such as a seeded *math/rand.Rand
+// to make jittered delays reproducible in tests. *math/rand.Rand is not safe for concurrent use:
+// do not share one across retries executing concurrently.
+//
+// Parameters:
+//   - random: Random source. Cannot be nil. Defaults to crypto/rand.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback,
+//	    WithJitter(NewFullJitter()),
+//	    WithRandomSource(rand.New(rand.NewSource(42))),
+//	)
+func WithRandomSource(random RandomSource) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if random == nil {
+			return invalidOption("random source cannot be nil")
+		}
+
+		cfg.random = random
+
+		return nil
+	})
+}
+
+// WithOnRetry registers a hook called before each sleep between attempts, with the attempt
+// number, its error, the delay about to be waited, its jitter and the elapsed time.
+// Several hooks can be registered; they are called in registration order.
+//
+// Parameters:
+//   - hook: Function receiving the context and the event. Cannot be nil.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback,
+//	    WithOnRetry(func(ctx context.Context, event RetryEvent) {
+//	        retriesCounter.Inc()
+//	        log.Printf("attempt %d failed: %v, retrying in %s", event.Attempt, event.Err, event.Delay)
+//	    }),
+//	)
+func WithOnRetry(hook RetryHook) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if hook == nil {
+			return invalidOption("retry hook cannot be nil")
+		}
+
+		cfg.hooks.onRetry = append(cfg.hooks.onRetry, hook)
+
+		return nil
+	})
+}
+
+// WithOnGiveUp registers a hook called when the retry returns an error: attempts exhausted,
+// non-retryable error, elapsed time budget exceeded or context done.
+// Several hooks can be registered; they are called in registration order.
+//
+// Parameters:
+//   - hook: Function receiving the context and the event. Cannot be nil.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback,
+//	    WithOnGiveUp(func(ctx context.Context, event RetryEvent) {
+//	        log.Printf("giving up after %d attempts in %s: %v", event.Attempt, event.Elapsed, event.Err)
+//	    }),
+//	)
+func WithOnGiveUp(hook RetryHook) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if hook == nil {
+			return invalidOption("give up hook cannot be nil")
+		}
+
+		cfg.hooks.onGiveUp = append(cfg.hooks.onGiveUp, hook)
+
+		return nil
+	})
+}
+
+// WithOnSuccess registers a hook called when an attempt succeeds, with the number of attempts
+// it took and the elapsed time. Several hooks can be registered; they are called in registration order.
+//
+// Parameters:
+//   - hook: Function receiving the context and the event. Cannot be nil.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback,
+//	    WithOnSuccess(func(ctx context.Context, event RetryEvent) {
+//	        attemptsHistogram.Observe(float64(event.Attempt))
+//	    }),
+//	)
+func WithOnSuccess(hook RetryHook) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if hook == nil {
+			return invalidOption("success hook cannot be nil")
+		}
+
+		cfg.hooks.onSuccess = append(cfg.hooks.onSuccess, hook)
+
+		return nil
+	})
+}
+
+// WithRetryAfterMode sets how the delay hint of an error implementing RetryAfterHint, such as
+// one returned by RetryAfter, is applied to the delay computed by the backoff and jitter.
+//
+// Parameters:
+//   - mode: RetryAfterFloor (default) waits at least the hint, RetryAfterOverride waits exactly
+//     the hint, RetryAfterIgnore ignores hints.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithRetryAfterMode(RetryAfterOverride))
+func WithRetryAfterMode(mode RetryAfterMode) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		switch mode {
+		case RetryAfterFloor, RetryAfterOverride, RetryAfterIgnore:
+		default:
+			return invalidOption("unknown retry after mode %d", mode)
+		}
+
+		cfg.retryAfterMode = mode
+
+		return nil
+	})
+}
+
+// WithMaxRetryAfter caps delay hints from errors implementing RetryAfterHint, so that a server
+// asking to come back in an hour does not park the retry for an hour. Hints above the cap are
+// lowered to it; WithMaxElapsedTime and the context deadline still apply.
+//
+// Parameters:
+//   - maxRetryAfter: Maximum hinted delay honored. Must be greater than 0. Defaults to no cap.
+//
+// Example:
+//
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithMaxRetryAfter(time.Minute))
+func WithMaxRetryAfter(maxRetryAfter time.Duration) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if maxRetryAfter <= 0 {
+			return invalidOption("max retry after must be greater than 0, got %s", maxRetryAfter)
+		}
+
+		cfg.maxRetryAfter = maxRetryAfter
+
+		return nil
+	})
+}
+
+// WithRetryBudget makes retries draw from a budget shared with other retries, such as every
+// retry calling the same dependency. Successful calls replenish the budget; when it is spent,
+// the retry gives up with a *RetryError matching ErrRetryBudgetExhausted instead of sleeping.
+//
+// Parameters:
+//   - budget: The shared retry budget, created with NewRetryBudget. Cannot be nil.
+//
+// Example:
+//
+//	budget := NewRetryBudget(0.2, 10, 10*time.Second)
+//	retry, err := NewExponentialBackoffRetryWithOptions(callback, WithRetryBudget(budget))
+func WithRetryBudget(budget *RetryBudget) RetryOption {
+	return retryOptionFunc(func(cfg *retryConfig) error {
+		if budget == nil {
+			return invalidOption("retry budget cannot be nil")
+		}
+
+		cfg.budget = budget
+
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+package gendure
+
+import "context"
+
+// RetryPolicy is a reusable retry configuration that is not bound to an operation or a result type.
+// Configure one policy per dependency and share it across every call site with Do and DoAttempt.
+// A RetryPolicy is immutable once created and safe for concurrent use, provided the configured
+// backoff, jitter, random source, predicates and hooks are.
+//
+// Example:
+//
+//	var paymentsPolicy, _ = gendure.NewRetryPolicy(
+//	    gendure.WithMaxRetries(5),
+//	    gendure.WithBackoff(gendure.NewExponentialBackoff(100*time.Millisecond, 2)),
+//	    gendure.WithJitter(gendure.NewFullJitter()),
+//	)
+//
+//	charge, err := gendure.Do(ctx, paymentsPolicy, func(ctx context.Context) (*Charge, error) {
+//	    return payments.Charge(ctx, order)
+//	})
+type RetryPolicy struct {
+	cfg retryConfig
+}
+
+// NewRetryPolicy creates a reusable retry policy from the given options.
+// It accepts every RetryOption, with the same defaults as NewExponentialBackoffRetryWithOptions.
+//
+// A result predicate set with WithRetryOnResult only applies to calls whose result type
+// matches the predicate; calls returning another type ignore it.
+//
+// Parameters:
+//   - opts: Settings such as WithMaxRetries, WithBackoff, WithRetryIf and WithOnRetry.
+//
+// Returns:
+//   - *RetryPolicy: A configured policy ready to be shared
+//   - error: Every invalid setting joined together, each wrapping ErrInvalidOption
+//
+// Example:
+//
+//	policy, err := NewRetryPolicy(
+//	    WithMaxRetries(3),
+//	    WithMaxElapsedTime(10*time.Second),
+//	    WithRetryIf(isTransient),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+func NewRetryPolicy(opts ...RetryOption) (*RetryPolicy, error) {
+	cfg, err := newRetryConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the delay curve once so every call shares it
+	cfg.backoff = cfg.newBackoff()
+	cfg.jitter = cfg.newJitter()
+
+	return &RetryPolicy{cfg: cfg}, nil
+}
+
+// Do runs operation under the given retry policy and returns its result.
+// It behaves exactly like ExponentialBackoffRetry.Do with the policy's settings, so the
+// returned errors are the same as those of ExponentialBackoffRetry.Execute.
+//
+// Type Parameters:
+//   - T: The return type of the operation
+//
+// Parameters:
+//   - ctx: Context for cancellation control, parent of every attempt context
+//   - policy: The retry policy to apply. A nil policy uses the default settings.
+//   - operation: The function to execute and retry on failure
+//
+// Returns:
+//   - T: The result from the operation if any attempt succeeds, or zero value otherwise
+//   - error: Same as ExponentialBackoffRetry.Execute
+//
+// Example:
+//
+//	user, err := gendure.Do(ctx, policy, func(ctx context.Context) (*User, error) {
+//	    return users.Get(ctx, id)
+//	})
+func Do[T any](ctx context.Context, policy *RetryPolicy, operation func(ctx context.Context) (T, error)) (T, error) {
+	return DoAttempt(ctx, policy, withoutAttempt(operation))
+}
+
+// DoAttempt runs operation under the given retry policy like Do, passing it the attempt it is called for.
+//
+// Type Parameters:
+//   - T: The return type of the operation
+//
+// Parameters:
+//   - ctx: Context for cancellation control, parent of every attempt context
+//   - policy: The retry policy to apply. A nil policy uses the default settings.
+//   - operation: The function to execute and retry on failure
+//
+// Returns:
+//   - T: The result from the operation if any attempt succeeds, or zero value otherwise
+//   - error: Same as ExponentialBackoffRetry.Execute
+//
+// Example:
+//
+//	body, err := gendure.DoAttempt(ctx, policy, func(ctx context.Context, attempt gendure.Attempt) ([]byte, error) {
+//	    log.Printf("attempt %d, %d left", attempt.Number, attempt.RemainingAttempts)
+//	    return fetch(ctx, url)
+//	})
+func DoAttempt[T any](ctx context.Context, policy *RetryPolicy, operation CallbackAttemptFunc[T]) (T, error) {
+	cfg := defaultRetryConfig()
+	if policy != nil {
+		cfg = policy.cfg
+	}
+
+	return newExponentialBackoffRetry(operation, cfg).run(ctx, operation)
+}
@@ -0,0 +1,121 @@
+//nolint:all // only test
+package gendure_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marincor/gendure"
+)
+
+func TestRetryPolicySharedAcrossTypes(t *testing.T) {
+	var retries atomic.Int32
+
+	policy, err := gendure.NewRetryPolicy(
+		gendure.WithInitialDelay(time.Millisecond),
+		gendure.WithMaxRetries(2),
+		gendure.WithOnRetry(func(context.Context, gendure.RetryEvent) {
+			retries.Add(1)
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	calls := 0
+
+	text, err := gendure.Do(context.Background(), policy, func(ctx context.Context) (string, error) {
+		calls++
+		if calls < 2 {
+			return "", errOperation
+		}
+
+		return success, nil
+	})
+	if err != nil || text != success {
+		t.Errorf("want '%s', got '%s' and %v", success, text, err)
+	}
+
+	number, err := gendure.DoAttempt(context.Background(), policy, func(ctx context.Context, attempt gendure.Attempt) (int, error) {
+		return 0, errOperation
+	})
+
+	var retryErr *gendure.RetryError
+	if !errors.As(err, &retryErr) || !errors.Is(err, gendure.ErrRetriesExhausted) || number != 0 {
+		t.Fatalf("want a RetryError with ErrRetriesExhausted, got %d and %v", number, err)
+	}
+
+	if len(retryErr.Failures) != 2 {
+		t.Errorf("want 2 failed attempts, got %d", len(retryErr.Failures))
+	}
+
+	if got := retries.Load(); got != 2 {
+		t.Errorf("want 2 retries across both calls, got %d", got)
+	}
+}
+
+func TestRetryPolicyResultPredicateAppliesToMatchingType(t *testing.T) {
+	policy, err := gendure.NewRetryPolicy(
+		gendure.WithInitialDelay(time.Millisecond),
+		gendure.WithRetryOnResult(func(status string) bool {
+			return status == "pending"
+		}),
+	)
+	if err != nil {
+		t.Fatalf(unexpected, err)
+	}
+
+	statuses := []string{"pending", "done"}
+	calls := 0
+
+	status, err := gendure.Do(context.Background(), policy, func(ctx context.Context) (string, error) {
+		status := statuses[calls]
+		calls++
+
+		return status, nil
+	})
+	if err != nil || status != "done" || calls != 2 {
+		t.Errorf("want 'done' after 2 calls, got '%s' and %v after %d calls", status, err, calls)
+	}
+
+	calls = 0
+
+	_, err = gendure.Do(context.Background(), policy, func(ctx context.Context) (int, error) {
+		calls++
+
+		return 0, nil
+	})
+	if err != nil || calls != 1 {
+		t.Errorf("want the predicate ignored for another result type, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryPolicyInvalidOptions(t *testing.T) {
+	policy, err := gendure.NewRetryPolicy(
+		gendure.WithMaxRetries(-1),
+		nil,
+	)
+	if !errors.Is(err, gendure.ErrInvalidOption) || policy != nil {
+		t.Errorf("want ErrInvalidOption and no policy, got %v and %v", policy, err)
+	}
+}
+
+func TestRetryPolicyNilUsesDefaults(t *testing.T) {
+	calls := 0
+
+	result, err := gendure.Do(context.Background(), nil, func(ctx context.Context) (string, error) {
+		calls++
+
+		return success, nil
+	})
+	if err != nil {
+		t.Errorf(errorWantSuccessGotError, err)
+	}
+
+	if result != success || calls != 1 {
+		t.Errorf(errorWant1CallGot, calls)
+	}
+}
@@ -0,0 +1,236 @@
+package gendure
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// callOutcome is a bit set describing how a single protected call ended.
+// The zero value marks an empty window slot.
+type callOutcome uint32
+
+const (
+	// outcomeRecorded marks a slot that holds a call result.
+	outcomeRecorded callOutcome = 1 << iota
+
+	// outcomeFailure marks a call that returned a failure.
+	outcomeFailure
+
+	// outcomeSlow marks a call that took longer than the slow call duration threshold.
+	outcomeSlow
+)
+
+// windowSnapshot is a point-in-time aggregate of the calls held by a sliding window.
+type windowSnapshot struct {
+	// calls is the number of calls currently inside the window.
+	calls int64
+
+	// failures is the number of failed calls currently inside the window.
+	failures int64
+
+	// slowCalls is the number of slow calls currently inside the window.
+	slowCalls int64
+}
+
+// failureRate returns the percentage (0-100) of failed calls in the snapshot.
+// Returns 0 when the snapshot holds no calls.
+func (s windowSnapshot) failureRate() float64 {
+	if s.calls <= 0 {
+		return 0
+	}
+
+	return float64(s.failures) * percent / float64(s.calls)
+}
+
+// slowCallRate returns the percentage (0-100) of slow calls in the snapshot.
+// Returns 0 when the snapshot holds no calls.
+func (s windowSnapshot) slowCallRate() float64 {
+	if s.calls <= 0 {
+		return 0
+	}
+
+	return float64(s.slowCalls) * percent / float64(s.calls)
+}
+
+// slidingWindow aggregates the outcomes of the most recent calls.
+// Implementations must be safe for concurrent use without blocking callers.
+type slidingWindow interface {
+	// record adds the outcome of a finished call to the window.
+	record(outcome callOutcome, now time.Time)
+
+	// snapshot returns the aggregated outcomes currently inside the window.
+	snapshot(now time.Time) windowSnapshot
+
+	// reset discards every outcome held by the window.
+	reset()
+}
+
+// windowCounters holds the atomic aggregates shared by every sliding window implementation.
+type windowCounters struct {
+	// calls is the number of recorded outcomes.
+	calls atomic.Int64
+
+	// failures is the number of failed outcomes.
+	failures atomic.Int64
+
+	// slowCalls is the number of slow outcomes.
+	slowCalls atomic.Int64
+}
+
+// apply adds delta to the aggregates matching the outcome bits.
+func (c *windowCounters) apply(outcome callOutcome, delta int64) {
+	if outcome&outcomeRecorded == 0 {
+		return
+	}
+
+	c.calls.Add(delta)
+
+	if outcome&outcomeFailure != 0 {
+		c.failures.Add(delta)
+	}
+
+	if outcome&outcomeSlow != 0 {
+		c.slowCalls.Add(delta)
+	}
+}
+
+// load returns the current aggregates as a snapshot.
+func (c *windowCounters) load() windowSnapshot {
+	return windowSnapshot{
+		calls:     c.calls.Load(),
+		failures:  c.failures.Load(),
+		slowCalls: c.slowCalls.Load(),
+	}
+}
+
+// clear sets every aggregate to zero.
+func (c *windowCounters) clear() {
+	c.calls.Store(0)
+	c.failures.Store(0)
+	c.slowCalls.Store(0)
+}
+
+// countWindow is a count-based sliding window holding the outcomes of the last N calls.
+// Outcomes are stored in a ring of atomic slots; aggregates are maintained incrementally
+// so snapshots are O(1) and recording never takes a lock.
+type countWindow struct {
+	windowCounters
+
+	// slots holds the outcome of each call in the ring.
+	slots []atomic.Uint32
+
+	// next is the monotonically increasing position of the next slot to write.
+	next atomic.Uint64
+}
+
+// newCountWindow creates a count-based sliding window of the given size.
+func newCountWindow(size int) *countWindow {
+	return &countWindow{
+		slots: make([]atomic.Uint32, size),
+	}
+}
+
+func (w *countWindow) record(outcome callOutcome, _ time.Time) {
+	position := (w.next.Add(1) - 1) % uint64(len(w.slots))
+	evicted := callOutcome(w.slots[position].Swap(uint32(outcome)))
+
+	w.apply(evicted, -1)
+	w.apply(outcome, 1)
+}
+
+func (w *countWindow) snapshot(_ time.Time) windowSnapshot {
+	return w.load()
+}
+
+func (w *countWindow) reset() {
+	for i := range w.slots {
+		w.apply(callOutcome(w.slots[i].Swap(0)), -1)
+	}
+}
+
+// timeBucket aggregates the outcomes of the calls finished during one second.
+type timeBucket struct {
+	windowCounters
+
+	// epoch is the Unix second the bucket currently aggregates.
+	epoch atomic.Int64
+}
+
+// timeWindow is a time-based sliding window aggregating outcomes into per-second buckets
+// over the last N seconds. Buckets are reused as a ring indexed by Unix second.
+//
+// Recording is lock-free while the current second's bucket is live; the rotation mutex is
+// only taken by the first call of each second to recycle a stale bucket, so contention stays
+// independent of the call rate.
+type timeWindow struct {
+	// buckets holds one aggregate per second of the window.
+	buckets []timeBucket
+
+	// rotation serializes recycling of stale buckets.
+	rotation sync.Mutex
+}
+
+// newTimeWindow creates a time-based sliding window covering size, rounded up to whole seconds.
+func newTimeWindow(size time.Duration) *timeWindow {
+	seconds := int((size + time.Second - 1) / time.Second)
+
+	return &timeWindow{
+		buckets: make([]timeBucket, seconds),
+	}
+}
+
+func (w *timeWindow) record(outcome callOutcome, now time.Time) {
+	w.bucket(now.Unix()).apply(outcome, 1)
+}
+
+func (w *timeWindow) snapshot(now time.Time) windowSnapshot {
+	var snapshot windowSnapshot
+
+	current := now.Unix()
+	oldest := current - int64(len(w.buckets))
+
+	for i := range w.buckets {
+		bucket := &w.buckets[i]
+
+		if epoch := bucket.epoch.Load(); epoch > oldest && epoch <= current {
+			counters := bucket.load()
+			snapshot.calls += counters.calls
+			snapshot.failures += counters.failures
+			snapshot.slowCalls += counters.slowCalls
+		}
+	}
+
+	return snapshot
+}
+
+func (w *timeWindow) reset() {
+	w.rotation.Lock()
+	defer w.rotation.Unlock()
+
+	for i := range w.buckets {
+		w.buckets[i].clear()
+		w.buckets[i].epoch.Store(0)
+	}
+}
+
+// bucket returns the bucket aggregating the given Unix second, recycling it when it still
+// holds outcomes from an older second. Counters are cleared before the new epoch is
+// published, so concurrent recorders never add to a bucket that is about to be cleared.
+// A late recorder whose second was already recycled adds to the newer bucket instead.
+func (w *timeWindow) bucket(second int64) *timeBucket {
+	bucket := &w.buckets[uint64(second)%uint64(len(w.buckets))]
+	if bucket.epoch.Load() >= second {
+		return bucket
+	}
+
+	w.rotation.Lock()
+	defer w.rotation.Unlock()
+
+	if bucket.epoch.Load() < second {
+		bucket.clear()
+		bucket.epoch.Store(second)
+	}
+
+	return bucket
+}
@@ -0,0 +1,72 @@
+//nolint:all // only test
+package gendure
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountWindowKeepsLastCalls(t *testing.T) {
+	window := newCountWindow(3)
+	now := time.Now()
+
+	window.record(outcomeRecorded|outcomeFailure, now)
+	window.record(outcomeRecorded|outcomeFailure, now)
+	window.record(outcomeRecorded, now)
+	window.record(outcomeRecorded, now)
+
+	snapshot := window.snapshot(now)
+	if snapshot.calls != 3 || snapshot.failures != 1 {
+		t.Errorf("expected 1 failure over 3 calls, got %d over %d", snapshot.failures, snapshot.calls)
+	}
+
+	window.reset()
+
+	if snapshot := window.snapshot(now); snapshot.calls != 0 || snapshot.failures != 0 {
+		t.Errorf("expected empty window after reset, got %+v", snapshot)
+	}
+}
+
+func TestTimeWindowAggregatesBucketsWithinSpan(t *testing.T) {
+	window := newTimeWindow(3 * time.Second)
+	start := time.Unix(1_000, 0)
+
+	window.record(outcomeRecorded|outcomeFailure, start)
+	window.record(outcomeRecorded, start.Add(500*time.Millisecond))
+	window.record(outcomeRecorded|outcomeFailure, start.Add(1*time.Second))
+	window.record(outcomeRecorded, start.Add(2*time.Second))
+
+	snapshot := window.snapshot(start.Add(2 * time.Second))
+	if snapshot.calls != 4 || snapshot.failures != 2 {
+		t.Errorf("expected 2 failures over 4 calls, got %d over %d", snapshot.failures, snapshot.calls)
+	}
+
+	if rate := snapshot.failureRate(); rate != 50 {
+		t.Errorf("expected failure rate 50, got %f", rate)
+	}
+
+	// The first second falls out of the window
+	snapshot = window.snapshot(start.Add(3 * time.Second))
+	if snapshot.calls != 2 || snapshot.failures != 1 {
+		t.Errorf("expected 1 failure over 2 calls, got %d over %d", snapshot.failures, snapshot.calls)
+	}
+}
+
+func TestTimeWindowRecyclesStaleBuckets(t *testing.T) {
+	window := newTimeWindow(2 * time.Second)
+	start := time.Unix(1_000, 0)
+
+	window.record(outcomeRecorded|outcomeFailure, start)
+	window.record(outcomeRecorded, start.Add(2*time.Second))
+
+	snapshot := window.snapshot(start.Add(2 * time.Second))
+	if snapshot.calls != 1 || snapshot.failures != 0 {
+		t.Errorf("expected recycled bucket to hold 1 success, got %+v", snapshot)
+	}
+
+	window.reset()
+
+	if snapshot := window.snapshot(start.Add(2 * time.Second)); snapshot.calls != 0 {
+		t.Errorf("expected empty window after reset, got %+v", snapshot)
+	}
+}